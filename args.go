@@ -0,0 +1,205 @@
+package gojs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// SetStrictArguments sets whether native functions and methods called from
+// ctx with more arguments than they take throw a TypeError. By default, the
+// surplus arguments are ignored, as JavaScript functions ignore them.
+func (ctx *Context) SetStrictArguments(strict bool) {
+	ctx.data().strictArguments = strict
+}
+
+// StrictArguments returns the setting of SetStrictArguments.
+func (ctx *Context) StrictArguments() bool {
+	return ctx.data().strictArguments
+}
+
+// newTypeErrorSource evaluates to a function creating a TypeError.
+const newTypeErrorSource = `(function (message) { return new TypeError(message); })`
+
+// newTypeError returns a TypeError with message as an exception, for native
+// callbacks to panic with.
+func (ctx *Context) newTypeError(message string) *errorValue {
+	v, err := ctx.callHelper(newTypeErrorSource, ctx.NewStringValue(message))
+	if err != nil {
+		panic("newTypeError: " + err.Error())
+	}
+	return &errorValue{ctx, v.ref}
+}
+
+// funcName returns the name of the Go function fn, for error messages.
+func funcName(fn reflect.Value) string {
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		return f.Name()
+	}
+	return "native function"
+}
+
+// This is the this object of a call to a native function, injected into a
+// leading parameter of type This.
+type This struct {
+	*Object
+}
+
+// CallInfo describes a call to a native function, injected into a leading
+// parameter of type CallInfo.
+type CallInfo struct {
+	Context   *Context
+	Function  *Object
+	This      *Object
+	Arguments []*Value // the arguments as passed, before conversion
+}
+
+// Rest holds the arguments of a call to a native function beyond those of
+// its other parameters, unconverted, when it is the type of the last one.
+type Rest []*Value
+
+var (
+	contextType   = reflect.TypeOf((*Context)(nil))
+	thisType      = reflect.TypeOf(This{})
+	callInfoType  = reflect.TypeOf(CallInfo{})
+	goContextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	restType      = reflect.TypeOf(Rest(nil))
+)
+
+// inject returns the value of a leading parameter of type typ, for the
+// special types native functions may take, or false for other types.
+func (call *CallInfo) inject(typ reflect.Type) (reflect.Value, bool) {
+	switch typ {
+	case contextType:
+		return reflect.ValueOf(call.Context), true
+	case thisType:
+		return reflect.ValueOf(This{call.This}), true
+	case callInfoType:
+		return reflect.ValueOf(*call), true
+	case goContextType:
+		return reflect.ValueOf(call.Context.GoContext()), true
+	}
+	return reflect.Value{}, false
+}
+
+// isInjected reports whether leading parameters of type typ are injected.
+func isInjected(typ reflect.Type) bool {
+	switch typ {
+	case contextType, thisType, callInfoType, goContextType:
+		return true
+	}
+	return false
+}
+
+// signature describes which parameters of a native function take the
+// arguments of a call.
+type signature struct {
+	typ     reflect.Type
+	lead    int  // the number of injected leading parameters
+	n       int  // the number of converted parameters, but a variadic one
+	hasRest bool // whether the last parameter is of type Rest
+}
+
+func signatureOf(typ reflect.Type) signature {
+	sig := signature{typ: typ}
+	params := typ.NumIn()
+	for sig.lead < params && isInjected(typ.In(sig.lead)) {
+		sig.lead++
+	}
+	sig.hasRest = !typ.IsVariadic() && params > sig.lead && typ.In(params-1) == restType
+	if sig.hasRest || typ.IsVariadic() {
+		params--
+	}
+	sig.n = params - sig.lead
+	return sig
+}
+
+// takesSurplus reports whether functions of signature sig take any number
+// of arguments beyond n.
+func (sig signature) takesSurplus() bool {
+	return sig.hasRest || sig.typ.IsVariadic()
+}
+
+// paramType returns the type argument i converts to.
+func (sig signature) paramType(i int) reflect.Type {
+	if i >= sig.n {
+		return sig.typ.In(sig.typ.NumIn() - 1).Elem()
+	}
+	return sig.typ.In(sig.lead + i)
+}
+
+// String returns the Go types the arguments convert to, like
+// "(string, ...int)".
+func (sig signature) String() string {
+	params := make([]string, 0, sig.n+1)
+	for i := 0; i < sig.n; i++ {
+		params = append(params, sig.paramType(i).String())
+	}
+	switch {
+	case sig.hasRest:
+		params = append(params, "...Rest")
+	case sig.typ.IsVariadic():
+		params = append(params, "..."+sig.paramType(sig.n).String())
+	}
+	return "(" + strings.Join(params, ", ") + ")"
+}
+
+// callArgs converts the arguments of call to the parameters of the native
+// function named name, of type typ.
+//
+// Leading parameters of type *Context, This, CallInfo and context.Context
+// are injected rather than converted, and a last parameter of type Rest
+// takes the surplus arguments. Missing trailing arguments convert as
+// undefined does, to zero values, so that pointer parameters tell them
+// apart as nil. Surplus arguments of variadic functions convert to the
+// element type of the last parameter; those of other functions are
+// ignored, unless StrictArguments is set. Arguments that cannot be
+// converted are rejected with a TypeError naming the function, the
+// argument and its type.
+func (ctx *Context) callArgs(name string, typ reflect.Type, call *CallInfo) ([]reflect.Value, error) {
+	in, err := ctx.convertArgs(signatureOf(typ), call)
+	if err != nil {
+		return nil, ctx.newTypeError(name + ": " + err.Error())
+	}
+	return in, nil
+}
+
+// convertArgs converts the arguments of call to the parameters of a native
+// function of signature sig, as described for callArgs.
+func (ctx *Context) convertArgs(sig signature, call *CallInfo) ([]reflect.Value, error) {
+	var in []reflect.Value
+	for i := 0; i < sig.lead; i++ {
+		v, _ := call.inject(sig.typ.In(i))
+		in = append(in, v)
+	}
+
+	args := call.Arguments
+	var rest Rest
+	if !sig.typ.IsVariadic() && len(args) > sig.n {
+		if sig.hasRest {
+			rest = args[sig.n:]
+		} else if ctx.StrictArguments() {
+			return nil, fmt.Errorf("takes %d arguments, got %d", sig.n, len(args))
+		}
+		args = args[:sig.n]
+	}
+
+	for i := 0; i < max(sig.n, len(args)); i++ {
+		paramType := sig.paramType(i)
+		arg := ctx.NewUndefinedValue()
+		if i < len(args) {
+			arg = args[i]
+		}
+		r, err := arg.convertTo(paramType)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d must be %v: %s", i, paramType, strings.TrimPrefix(err.Error(), "gojs: "))
+		}
+		in = append(in, r)
+	}
+	if sig.hasRest {
+		in = append(in, reflect.ValueOf(rest))
+	}
+	return in, nil
+}
@@ -0,0 +1,98 @@
+package gojs
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNativeFunctionArguments(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	global := ctx.GlobalObject()
+	global.SetProperty("join", ctx.NewFunctionWithNative(func(sep string, xs ...string) string {
+		return strings.Join(xs, sep)
+	}).ToValue(), 0)
+	global.SetProperty("greet", ctx.NewFunctionWithNative(func(name string, title *string) string {
+		if title == nil {
+			return "hello " + name
+		}
+		return "hello " + *title + " " + name
+	}).ToValue(), 0)
+	global.SetProperty("add", ctx.NewFunctionWithNative(func(a, b int) int {
+		return a + b
+	}).ToValue(), 0)
+
+	checkScript(t, ctx, "join('-', 'a', 'b', 'c')", "a-b-c")
+	checkScript(t, ctx, "join('-')", "")
+	checkScript(t, ctx, "greet('ada')", "hello ada")
+	checkScript(t, ctx, "greet('ada', undefined)", "hello ada")
+	checkScript(t, ctx, "greet('ada', 'dr')", "hello dr ada")
+	checkScript(t, ctx, "add(1)", "1")
+	checkScript(t, ctx, "add(1, 2, 3)", "3")
+
+	checkScript(t, ctx, "try { add(1, 'x') } catch (e) { e instanceof TypeError }", "true")
+	val, err := ctx.EvaluateScript("join('-', 'a', 2)", nil, "", 1)
+	if err == nil {
+		t.Fatalf("join with a number returned %v, want a TypeError", val)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "TypeError") || !strings.Contains(msg, "argument 2 must be string") ||
+		!strings.Contains(msg, "TestNativeFunctionArguments") {
+		t.Errorf("join with a number threw %q, want a TypeError naming the function, argument and type", msg)
+	}
+
+	ctx.SetStrictArguments(true)
+	checkScript(t, ctx, "try { add(1, 2, 3) } catch (e) { e instanceof TypeError }", "true")
+	checkScript(t, ctx, "join('-', 'a', 'b', 'c')", "a-b-c")
+}
+
+func TestNativeMethodArguments(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	obj := &reflect_object{1, 2, 3.0, "four"}
+	ctx.GlobalObject().SetProperty("n", ctx.NewNativeObject(obj).ToValue(), 0)
+
+	checkScript(t, ctx, "n.AddWith()", "4")
+	checkScript(t, ctx, "n.AddWith(1, 'ignored')", "5")
+	_, err := ctx.EvaluateScript("n.AddWith('x')", nil, "", 1)
+	if err == nil || !strings.Contains(err.Error(), "AddWith: argument 0 must be float64") {
+		t.Errorf("n.AddWith('x') returned %v, want a TypeError naming AddWith", err)
+	}
+}
+
+func TestNativeFunctionInjection(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	type key struct{}
+	ctx.SetGoContext(context.WithValue(context.Background(), key{}, "request-1"))
+
+	global := ctx.GlobalObject()
+	global.SetProperty("describe", ctx.NewFunctionWithNative(func(this This, c context.Context, prefix string) string {
+		name, _ := Get[string](this.Object, "name")
+		return prefix + name + ":" + c.Value(key{}).(string)
+	}).ToValue(), 0)
+	global.SetProperty("count", ctx.NewFunctionWithNative(func(ctx *Context, info CallInfo, first string, rest Rest) int {
+		if info.Context != ctx || len(info.Arguments) != len(rest)+1 {
+			return -1
+		}
+		return len(rest)
+	}).ToValue(), 0)
+	global.SetProperty("tail", ctx.NewFunctionWithNative(func(first string, rest Rest) string {
+		if len(rest) == 0 {
+			return first
+		}
+		return rest[len(rest)-1].String()
+	}).ToValue(), 0)
+
+	checkScript(t, ctx, "({ name: 'box', describe: describe }).describe('> ')", "> box:request-1")
+	checkScript(t, ctx, "count('a', 1, {}, null)", "3")
+	checkScript(t, ctx, "count('a')", "0")
+	checkScript(t, ctx, "tail('a', 'b', 'c')", "c")
+	checkScript(t, ctx, "tail('a')", "a")
+
+	ctx.SetStrictArguments(true)
+	checkScript(t, ctx, "tail('a', 'b', 'c')", "c")
+}
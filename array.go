@@ -0,0 +1,178 @@
+package gojs
+
+// #include <stdlib.h>
+// #include <JavaScriptCore/JSStringRef.h>
+// #include <JavaScriptCore/JSObjectRef.h>
+//
+// // gojs_get_elements reads count elements of obj starting at start into
+// // values, stopping at the first exception. It returns the number of
+// // elements read. They are protected, since values is not scanned by the
+// // garbage collector that getters of later elements may run.
+// static size_t gojs_get_elements(JSContextRef ctx, JSObjectRef obj, unsigned start, size_t count, JSValueRef *values, JSValueRef *exception)
+// {
+// 	size_t i;
+// 	for (i = 0; i < count; i++) {
+// 		values[i] = JSObjectGetPropertyAtIndex(ctx, obj, start + (unsigned)i, exception);
+// 		if (*exception) {
+// 			break;
+// 		}
+// 		JSValueProtect(ctx, values[i]);
+// 	}
+// 	return i;
+// }
+import "C"
+import (
+	"errors"
+	"math"
+)
+
+// maxArrayIndex is the largest valid array index, 2^32-2.
+const maxArrayIndex = math.MaxUint32 - 1
+
+// ErrIndexOutOfRange is returned for array indices that are negative or
+// larger than the largest JavaScript array index.
+var ErrIndexOutOfRange = errors.New("gojs: array index out of range")
+
+// Len returns the length property of obj, which for arrays is the number of
+// elements.
+func (obj *Object) Len() (int, error) {
+	length, err := obj.GetProperty("length")
+	if err != nil {
+		return 0, err
+	}
+	n, err := length.ToNumber()
+	if err != nil {
+		return 0, err
+	}
+	if n != n || n < 0 {
+		return 0, nil
+	}
+	if n > math.MaxInt {
+		return math.MaxInt, nil
+	}
+	return int(n), nil
+}
+
+// At returns the element of obj at index i. Elements past the end of an
+// array are undefined.
+func (obj *Object) At(i int) (*Value, error) {
+	if i < 0 || uint64(i) > maxArrayIndex {
+		return nil, ErrIndexOutOfRange
+	}
+	return obj.GetPropertyAtIndex(uint32(i))
+}
+
+// Set sets the element of obj at index i to v. Arrays grow as needed.
+func (obj *Object) Set(i int, v *Value) error {
+	if i < 0 || uint64(i) > maxArrayIndex {
+		return ErrIndexOutOfRange
+	}
+	return obj.SetPropertyAtIndex(uint32(i), v)
+}
+
+// Push appends values to the array obj with Array.prototype.push and
+// returns its new length.
+func (obj *Object) Push(values ...*Value) (int, error) {
+	ret, err := obj.callArrayMethod("push", values)
+	if err != nil {
+		return 0, err
+	}
+	return int(ret.ToNumberOrDie()), nil
+}
+
+// Slice returns a new array holding the elements of obj from start up to
+// but not including end, like Array.prototype.slice. Negative indices count
+// back from the end of the array.
+func (obj *Object) Slice(start, end int) (*Object, error) {
+	ctx := obj.ctx
+	ret, err := obj.callArrayMethod("slice", []*Value{
+		ctx.NewNumberValue(float64(start)),
+		ctx.NewNumberValue(float64(end)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret.ToObject()
+}
+
+// callArrayMethod calls the method of Array.prototype called name on obj,
+// which need not be an array.
+func (obj *Object) callArrayMethod(name string, args []*Value) (*Value, error) {
+	array, err := obj.ctx.GlobalObject().GetProperty("Array")
+	if err != nil {
+		return nil, err
+	}
+	proto, err := array.ToObjectOrDie().GetProperty("prototype")
+	if err != nil {
+		return nil, err
+	}
+	method, err := proto.ToObjectOrDie().GetProperty(name)
+	if err != nil {
+		return nil, err
+	}
+	return method.ToObjectOrDie().CallAsFunction(obj, args)
+}
+
+// elementsBatch is the number of elements ToSlice reads from JavaScriptCore
+// at a time, so that it allocates as it reads rather than trusting the
+// length property.
+const elementsBatch = 1024
+
+// ToSlice returns the elements of obj, from index 0 up to its length. The
+// elements are read in batches, with a single call into JavaScriptCore for
+// each, so this is much faster than calling At for each of them. Lengths
+// beyond that of the largest array return ErrIndexOutOfRange.
+func (obj *Object) ToSlice() ([]*Value, error) {
+	n, err := obj.Len()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(n) > maxArrayIndex+1 {
+		return nil, ErrIndexOutOfRange
+	}
+
+	values := make([]*Value, 0, min(n, elementsBatch))
+	defer func() { unprotectValues(values) }()
+	for len(values) < n {
+		start := len(values)
+		values = append(values, make([]*Value, min(n-start, elementsBatch))...)
+		read, err := obj.readElements(start, values[start:])
+		if err != nil {
+			values = values[:start+read]
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// readElements reads the elements of obj starting at index start into
+// values, in a single call into JavaScriptCore. It stops at the first
+// exception, returning the number of elements read before it. The elements
+// read are protected from garbage collection, so that getters run by later
+// reads cannot collect them; callers unprotect them with unprotectValues.
+func (obj *Object) readElements(start int, values []*Value) (int, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+	if start < 0 || uint64(start)+uint64(len(values))-1 > maxArrayIndex {
+		return 0, ErrIndexOutOfRange
+	}
+
+	refs := make([]C.JSValueRef, len(values))
+	errVal := obj.ctx.newErrorValue()
+	n := int(C.gojs_get_elements(obj.ctx.ref, obj.ref, C.unsigned(start), C.size_t(len(refs)), &refs[0], &errVal.ref))
+	for i := 0; i < n; i++ {
+		values[i] = obj.ctx.newValue(refs[i])
+	}
+	if errVal.ref != nil {
+		return n, errVal
+	}
+	return n, nil
+}
+
+// unprotectValues unprotects the elements read by readElements.
+func unprotectValues(values []*Value) {
+	for _, v := range values {
+		v.UnProtect()
+	}
+}
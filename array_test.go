@@ -0,0 +1,109 @@
+package gojs
+
+import (
+	"testing"
+)
+
+func TestArray(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	// Large enough for indices that do not fit in 16 bits.
+	val, err := ctx.EvaluateScript("var a = []; for (var i = 0; i < 70000; i++) a.push(i); a", nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	arr := val.ToObjectOrDie()
+
+	if n, err := arr.Len(); err != nil || n != 70000 {
+		t.Errorf("arr.Len() = %d, %v, want 70000", n, err)
+	}
+	if v, err := arr.At(65537); err != nil || v.ToNumberOrDie() != 65537 {
+		t.Errorf("arr.At(65537) = %v, %v, want 65537", v, err)
+	}
+	if _, err := arr.At(-1); err != ErrIndexOutOfRange {
+		t.Errorf("arr.At(-1) returned %v, want ErrIndexOutOfRange", err)
+	}
+
+	if err := arr.Set(70000, ctx.NewStringValue("end")); err != nil {
+		t.Errorf("arr.Set failed: %v", err)
+	}
+	if n, err := arr.Push(ctx.NewNumberValue(1), ctx.NewNumberValue(2)); err != nil || n != 70003 {
+		t.Errorf("arr.Push() = %d, %v, want 70003", n, err)
+	}
+
+	values, err := arr.ToSlice()
+	if err != nil {
+		t.Fatalf("arr.ToSlice failed: %v", err)
+	}
+	if len(values) != 70003 {
+		t.Fatalf("arr.ToSlice returned %d values, want 70003", len(values))
+	}
+	for _, i := range []int{0, 65535, 65536, 69999} {
+		if values[i].ToNumberOrDie() != float64(i) {
+			t.Errorf("values[%d] = %v, want %d", i, values[i], i)
+		}
+	}
+	if values[70000].ToStringOrDie() != "end" || values[70002].ToNumberOrDie() != 2 {
+		t.Errorf("values ends with %v, %v, %v, want end, 1, 2", values[70000], values[70001], values[70002])
+	}
+
+	slice, err := arr.Slice(-3, -1)
+	if err != nil {
+		t.Fatalf("arr.Slice failed: %v", err)
+	}
+	if json, _ := slice.ToValue().ToJSON(); string(json) != `["end",1]` {
+		t.Errorf("arr.Slice(-3, -1) = %s, want [\"end\",1]", json)
+	}
+}
+
+func TestArrayToSliceException(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript(`var a = [1, 2, 3];
+		Object.defineProperty(a, 1, { get: function () { throw new Error("boom"); } });
+		a`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	if _, err := val.ToObjectOrDie().ToSlice(); err == nil || err.Error() != "Error: boom" {
+		t.Errorf("ToSlice returned %v, want Error: boom", err)
+	}
+
+	empty, _ := ctx.NewArray(nil)
+	if values, err := empty.ToSlice(); err != nil || len(values) != 0 {
+		t.Errorf("ToSlice of an empty array returned %v, %v", values, err)
+	}
+}
+
+func TestArrayToSliceLength(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	for _, script := range []string{"({ length: Infinity })", "({ length: 4294967296 })"} {
+		val, err := ctx.EvaluateScript(script, nil, "", 1)
+		if err != nil {
+			t.Fatalf("%s: ctx.EvaluateScript failed: %v", script, err)
+		}
+		if _, err := val.ToObjectOrDie().ToSlice(); err != ErrIndexOutOfRange {
+			t.Errorf("%s: ToSlice returned %v, want ErrIndexOutOfRange", script, err)
+		}
+	}
+
+	// Getters may run the garbage collector between reads.
+	val, err := ctx.EvaluateScript(`var o = { length: 3, 1: "b" };
+		Object.defineProperty(o, 0, { get: function () { return { s: "a" }; } });
+		Object.defineProperty(o, 2, { get: function () { for (var i = 0; i < 1e5; i++) ({}); return "c"; } });
+		o`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	values, err := val.ToObjectOrDie().ToSlice()
+	if err != nil || len(values) != 3 {
+		t.Fatalf("ToSlice = %v, %v, want 3 values", values, err)
+	}
+	if s, err := Get[string](values[0].ToObjectOrDie(), "s"); err != nil || s != "a" {
+		t.Errorf("values[0].s = %q, %v, want a", s, err)
+	}
+}
@@ -1,13 +1,9 @@
 package gojs
 
 // #include <stdlib.h>
-// #cgo pkg-config: javascriptcoregtk-3.0
 // #include <JavaScriptCore/JSBase.h>
 import "C"
-import (
-	"log"
-	"unsafe"
-)
+import "unsafe"
 
 // EvaluateScript evaluates the JavaScript code in script.
 func (ctx *Context) EvaluateScript(script string, thisObject *Object, sourceURL string, startingLineNumber int) (*Value, error) {
@@ -20,12 +16,14 @@ func (ctx *Context) EvaluateScript(script string, thisObject *Object, sourceURL
 		defer sourceRef.Release()
 	}
 
+	return ctx.evaluate(scriptRef, thisObject, sourceRef, startingLineNumber)
+}
+
+func (ctx *Context) evaluate(scriptRef *String, thisObject *Object, sourceRef *String, startingLineNumber int) (*Value, error) {
 	if thisObject == nil {
 		thisObject = ctx.NewEmptyObject()
 	}
 
-	log.Println("About to evaluate script:", script, thisObject, sourceURL, startingLineNumber)
-
 	errVal := ctx.newErrorValue()
 	ret := C.JSEvaluateScript(ctx.ref,
 		C.JSStringRef(unsafe.Pointer(scriptRef)), thisObject.ref,
@@ -40,7 +38,8 @@ func (ctx *Context) EvaluateScript(script string, thisObject *Object, sourceURL
 	return ctx.newValue(ret), nil
 }
 
-// CheckScriptSyntax checks the JavaScript syntax of script.
+// CheckScriptSyntax checks the JavaScript syntax of script. Syntax errors
+// are returned as a *SyntaxError with the position of the error.
 func (ctx *Context) CheckScriptSyntax(script string, sourceURL string, startingLineNumber int) error {
 	scriptRef := NewString(script)
 	defer scriptRef.Release()
@@ -58,7 +57,7 @@ func (ctx *Context) CheckScriptSyntax(script string, sourceURL string, startingL
 	if !ret {
 		// A syntax error was found
 		// exception should be non-nil
-		return errVal
+		return ctx.newSyntaxError(errVal, sourceURL)
 	}
 
 	// exception should be nil
@@ -67,5 +66,6 @@ func (ctx *Context) CheckScriptSyntax(script string, sourceURL string, startingL
 
 // GarbageCollect performs a JavaScript garbage collection.
 func (ctx *Context) GarbageCollect() {
+	ctx.unprotectReleased()
 	C.JSGarbageCollect(ctx.ref)
 }
@@ -1,6 +1,7 @@
 package gojs
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -78,6 +79,31 @@ func TestCheckScript(t *testing.T) {
 	}
 }
 
+func TestCheckScriptSyntaxError(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	err := ctx.CheckScriptSyntax("var a = 1;\nvar b = ;", "bad.js", 10)
+	serr, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("ctx.CheckScriptSyntax returned %#v, want a *SyntaxError", err)
+	}
+	if serr.SourceURL != "bad.js" || serr.Line != 11 || serr.Column == 0 {
+		t.Errorf("syntax error at %s:%d:%d, want bad.js:11 and a column", serr.SourceURL, serr.Line, serr.Column)
+	}
+	if !strings.HasPrefix(serr.Error(), "SyntaxError: ") {
+		t.Errorf("serr.Error() = %q, want a SyntaxError message", serr.Error())
+	}
+}
+
+func TestSyntaxErrorWithoutException(t *testing.T) {
+	ctx := new(Context)
+	serr := ctx.newSyntaxError(&errorValue{ctx: ctx}, "bad.js")
+	if serr.Error() != "SyntaxError" || serr.SourceURL != "bad.js" {
+		t.Errorf("syntax error without an exception = %#v", serr)
+	}
+}
+
 func TestGarbageCollect(t *testing.T) {
 	ctx := NewContext()
 	defer ctx.Release()
@@ -0,0 +1,177 @@
+package gojs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+// maxSafeInteger is the largest integer up to which numbers represent every
+// integer exactly, Number.MAX_SAFE_INTEGER.
+const maxSafeInteger = 1<<53 - 1
+
+// LargeIntPolicy says how Go integers that numbers cannot represent exactly,
+// those beyond ±(2^53-1), convert to JavaScript.
+type LargeIntPolicy int
+
+const (
+	// LargeIntFloat converts large integers to the nearest number, as
+	// other integers convert. It is the default.
+	LargeIntFloat LargeIntPolicy = iota
+	// LargeIntBigInt converts large integers to BigInts, which needs a
+	// JavaScriptCore with BigInt support.
+	LargeIntBigInt
+	// LargeIntString converts large integers to strings of their decimal
+	// digits.
+	LargeIntString
+	// LargeIntError fails the conversion with an error wrapping
+	// ErrLargeInt.
+	LargeIntError
+)
+
+// ErrLargeInt is wrapped by the errors returned when converting an integer
+// that numbers cannot represent exactly under LargeIntError.
+var ErrLargeInt = errors.New("gojs: integer cannot be represented exactly as a number")
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+// SetLargeIntPolicy sets how the Go integers that native functions and
+// objects return to ctx convert when they are too large for numbers.
+func (ctx *Context) SetLargeIntPolicy(policy LargeIntPolicy) {
+	ctx.data().largeIntPolicy = policy
+}
+
+// LargeIntPolicy returns the policy set by SetLargeIntPolicy.
+func (ctx *Context) LargeIntPolicy() LargeIntPolicy {
+	return ctx.data().largeIntPolicy
+}
+
+// newBigIntSource evaluates to a function converting a string of decimal
+// digits to a BigInt.
+const newBigIntSource = `(function (digits) {
+	if (typeof BigInt !== "function") {
+		throw new TypeError("BigInt is not supported by this JavaScriptCore");
+	}
+	return BigInt(digits);
+})`
+
+// NewBigInt creates a BigInt with the value of x, which must not be nil.
+func (ctx *Context) NewBigInt(x *big.Int) (*Value, error) {
+	return ctx.callHelper(newBigIntSource, ctx.NewStringValue(x.String()))
+}
+
+// BigInt returns the value of the BigInt v. Numbers that are integers are
+// converted too.
+func (v *Value) BigInt() (*big.Int, error) {
+	switch v.Kind() {
+	case KindBigInt:
+		digits, err := v.ToString()
+		if err != nil {
+			return nil, err
+		}
+		x, ok := new(big.Int).SetString(digits, 10)
+		if !ok {
+			return nil, fmt.Errorf("gojs: cannot parse BigInt %q", digits)
+		}
+		return x, nil
+	case KindNumber:
+		n := v.ToNumberOrDie()
+		if n != math.Trunc(n) || math.IsInf(n, 0) {
+			return nil, fmt.Errorf("gojs: %v is not an integer", n)
+		}
+		x, _ := big.NewFloat(n).Int(nil)
+		return x, nil
+	}
+	return nil, fmt.Errorf("gojs: %v is not a BigInt", v.Kind())
+}
+
+// newIntValue converts n to a number, or following the large integer policy
+// of ctx if numbers cannot represent it exactly.
+func (ctx *Context) newIntValue(n int64) (*Value, error) {
+	if n >= -maxSafeInteger && n <= maxSafeInteger {
+		return ctx.NewNumberValue(float64(n)), nil
+	}
+	return ctx.newLargeIntValue(big.NewInt(n))
+}
+
+// newUintValue is like newIntValue, for unsigned integers.
+func (ctx *Context) newUintValue(n uint64) (*Value, error) {
+	if n <= maxSafeInteger {
+		return ctx.NewNumberValue(float64(n)), nil
+	}
+	return ctx.newLargeIntValue(new(big.Int).SetUint64(n))
+}
+
+func (ctx *Context) newLargeIntValue(x *big.Int) (*Value, error) {
+	switch ctx.LargeIntPolicy() {
+	case LargeIntBigInt:
+		return ctx.NewBigInt(x)
+	case LargeIntString:
+		return ctx.NewStringValue(x.String()), nil
+	case LargeIntError:
+		return nil, fmt.Errorf("%w: %v", ErrLargeInt, x)
+	}
+	f, _ := new(big.Float).SetInt(x).Float64()
+	return ctx.NewNumberValue(f), nil
+}
+
+// jsValueToInt converts the number or BigInt value to a value of the
+// integer type typ. Numbers that are not integers and values out of the
+// range of typ are errors, rather than being truncated.
+func jsValueToInt(value *Value, typ reflect.Type) (reflect.Value, error) {
+	r := reflect.New(typ).Elem()
+	unsigned := isUintKind(typ.Kind())
+
+	if value.IsNumber() {
+		n := value.ToNumberOrDie()
+		if n != math.Trunc(n) || math.IsInf(n, 0) {
+			return reflect.Value{}, fmt.Errorf("gojs: %v is not an integer", n)
+		}
+		// Limits of typ, which floats represent exactly.
+		lower, upper := -math.Ldexp(1, typ.Bits()-1), math.Ldexp(1, typ.Bits()-1)
+		if unsigned {
+			lower, upper = 0, 2*upper
+		}
+		if n < lower || n >= upper {
+			return reflect.Value{}, fmt.Errorf("gojs: %v is out of the range of %v", n, typ)
+		}
+		if unsigned {
+			r.SetUint(uint64(n))
+		} else {
+			r.SetInt(int64(n))
+		}
+		return r, nil
+	}
+
+	x, err := value.BigInt()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	switch {
+	case unsigned && x.IsUint64() && !r.OverflowUint(x.Uint64()):
+		r.SetUint(x.Uint64())
+	case !unsigned && x.IsInt64() && !r.OverflowInt(x.Int64()):
+		r.SetInt(x.Int64())
+	default:
+		return reflect.Value{}, fmt.Errorf("gojs: %v is out of the range of %v", x, typ)
+	}
+	return r, nil
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUintKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
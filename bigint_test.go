@@ -0,0 +1,160 @@
+package gojs
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestNewBigInt(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	x, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	val, err := ctx.NewBigInt(x)
+	if err != nil {
+		t.Fatalf("ctx.NewBigInt failed: %v", err)
+	}
+	if !val.IsBigInt() {
+		t.Fatalf("ctx.NewBigInt returned a %v", val.Kind())
+	}
+	ctx.GlobalObject().SetProperty("x", val, 0)
+	checkScript(t, ctx, "(x * 2n).toString()", "-246913578024691357802469135780")
+
+	back, err := val.BigInt()
+	if err != nil || back.Cmp(x) != 0 {
+		t.Errorf("val.BigInt() = %v, %v, want %v", back, err, x)
+	}
+}
+
+func TestValue_BigInt(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	tests := []struct {
+		script string
+		want   string // "" for an error
+	}{
+		{"2n ** 70n", "1180591620717411303424"},
+		{"-5n", "-5"},
+		{"1e21", "1000000000000000000000"},
+		{"1.5", ""},
+		{"NaN", ""},
+		{"'12'", ""},
+	}
+	for _, test := range tests {
+		val, err := ctx.EvaluateScript(test.script, nil, "", 1)
+		if err != nil {
+			t.Errorf("%s: ctx.EvaluateScript failed: %v", test.script, err)
+			continue
+		}
+		x, err := val.BigInt()
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("%s: BigInt() = %v, want an error", test.script, x)
+			}
+			continue
+		}
+		if err != nil || x.String() != test.want {
+			t.Errorf("%s: BigInt() = %v, %v, want %s", test.script, x, err, test.want)
+		}
+	}
+}
+
+func TestLargeIntPolicy(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	if ctx.LargeIntPolicy() != LargeIntFloat {
+		t.Errorf("default ctx.LargeIntPolicy() = %d, want LargeIntFloat", ctx.LargeIntPolicy())
+	}
+
+	const id int64 = 1<<60 + 1
+	tests := []struct {
+		policy LargeIntPolicy
+		kind   Kind
+		value  string
+	}{
+		{LargeIntFloat, KindNumber, "1152921504606846976"},
+		{LargeIntBigInt, KindBigInt, "1152921504606846977"},
+		{LargeIntString, KindString, "1152921504606846977"},
+	}
+	for _, test := range tests {
+		ctx.SetLargeIntPolicy(test.policy)
+		val := ctx.reflectToJSValue(reflect.ValueOf(id))
+		if val.Kind() != test.kind || val.String() != test.value {
+			t.Errorf("policy %d: converted to %v %s, want %v %s", test.policy, val.Kind(), val, test.kind, test.value)
+		}
+		// Small integers stay numbers.
+		if val := ctx.reflectToJSValue(reflect.ValueOf(int64(42))); !val.IsNumber() {
+			t.Errorf("policy %d: 42 converted to a %v", test.policy, val.Kind())
+		}
+	}
+
+	ctx.SetLargeIntPolicy(LargeIntError)
+	if ctx.LargeIntPolicy() != LargeIntError {
+		t.Errorf("ctx.LargeIntPolicy() = %d, want LargeIntError", ctx.LargeIntPolicy())
+	}
+	if _, err := ctx.newUintValue(math.MaxUint64); !errors.Is(err, ErrLargeInt) {
+		t.Errorf("converting MaxUint64 returned %v, want ErrLargeInt", err)
+	}
+}
+
+func TestJSValueToInt(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	tests := []struct {
+		script string
+		typ    reflect.Type
+		want   interface{} // nil for an error
+	}{
+		{"42", reflect.TypeOf(int(0)), 42},
+		{"-128", reflect.TypeOf(int8(0)), int8(-128)},
+		{"128", reflect.TypeOf(int8(0)), nil},
+		{"1.5", reflect.TypeOf(int(0)), nil},
+		{"Infinity", reflect.TypeOf(int64(0)), nil},
+		{"-1", reflect.TypeOf(uint(0)), nil},
+		{"4294967295", reflect.TypeOf(uint32(0)), uint32(math.MaxUint32)},
+		{"9223372036854775807n", reflect.TypeOf(int64(0)), int64(math.MaxInt64)},
+		{"9223372036854775808n", reflect.TypeOf(int64(0)), nil},
+		{"18446744073709551615n", reflect.TypeOf(uint64(0)), uint64(math.MaxUint64)},
+		{"-1n", reflect.TypeOf(uint16(0)), nil},
+	}
+	for _, test := range tests {
+		val, err := ctx.EvaluateScript(test.script, nil, "", 1)
+		if err != nil {
+			t.Errorf("%s: ctx.EvaluateScript failed: %v", test.script, err)
+			continue
+		}
+		r, err := jsValueToInt(val, test.typ)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("%s to %v = %v, want an error", test.script, test.typ, r)
+			}
+			continue
+		}
+		if err != nil || r.Interface() != test.want {
+			t.Errorf("%s to %v = %v, %v, want %v", test.script, test.typ, r, err, test.want)
+		}
+	}
+}
+
+func TestNativeFunctionBigIntArgs(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	next := ctx.NewFunctionWithNative(func(id uint64) uint64 { return id + 1 })
+	square := ctx.NewFunctionWithNative(func(x *big.Int) *big.Int { return new(big.Int).Mul(x, x) })
+	ctx.GlobalObject().SetProperty("next", next.ToValue(), 0)
+	ctx.GlobalObject().SetProperty("square", square.ToValue(), 0)
+
+	checkScript(t, ctx, "next(18446744073709551614n) === 18446744073709551615n", "true")
+	checkScript(t, ctx, "next(1)", "2")
+	checkScript(t, ctx, "square(2n ** 64n) === 2n ** 128n", "true")
+	if _, err := ctx.EvaluateScript("next(0.5)", nil, "", 1); err == nil {
+		t.Errorf("passing 0.5 for a uint64 succeeded")
+	}
+}
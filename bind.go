@@ -0,0 +1,249 @@
+package gojs
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// BindFunc sets the function variable fnPtr points to, such as a
+// *func(Props) (string, error), to a Go function calling the JavaScript
+// function jsFn. Calls convert their arguments with ValueOf, and plain data
+// such as structs, maps and slices through its JSON representation, and the
+// result of jsFn to the declared result type, as described for convertTo.
+//
+// The function may have no results, one result, an error, or one result and
+// an error. Exceptions thrown by jsFn, and results that cannot be converted,
+// are returned as the error result; without one, the function panics with
+// them instead.
+//
+// jsFn is protected from garbage collection for as long as the Go function
+// is reachable. Like all uses of a context, calls must be made from the
+// thread that owns it.
+func (ctx *Context) BindFunc(jsFn *Object, fnPtr interface{}) error {
+	ptr := reflect.ValueOf(fnPtr)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Func {
+		return fmt.Errorf("gojs: BindFunc needs a pointer to a function variable, not %T", fnPtr)
+	}
+	if !jsFn.IsFunction() {
+		return &NotAFunctionError{Kind: KindObject}
+	}
+	fn, err := ctx.makeFunc(jsFn, nil, ptr.Elem().Type())
+	if err != nil {
+		return err
+	}
+	ptr.Elem().Set(fn)
+	return nil
+}
+
+// makeFunc makes a Go function of type typ calling the JavaScript function
+// fn with this as this, as described for BindFunc.
+func (ctx *Context) makeFunc(fn, this *Object, typ reflect.Type) (reflect.Value, error) {
+	var resultType reflect.Type
+	hasError := false
+	switch n := typ.NumOut(); {
+	case n == 0:
+	case n == 1 && typ.Out(0) == errorType:
+		hasError = true
+	case n == 1:
+		resultType = typ.Out(0)
+	case n == 2 && typ.Out(1) == errorType:
+		resultType, hasError = typ.Out(0), true
+	default:
+		return reflect.Value{}, fmt.Errorf("gojs: cannot bind a JavaScript function to %v, which must return at most a value and an error", typ)
+	}
+
+	values := []*Value{fn.ToValue()}
+	if this != nil {
+		values = append(values, this.ToValue())
+	}
+	held := ctx.holdValues(values...)
+	return reflect.MakeFunc(typ, func(in []reflect.Value) []reflect.Value {
+		defer runtime.KeepAlive(held)
+		ctx.unprotectReleased()
+		var result reflect.Value
+		err := func() error {
+			args, err := ctx.funcArgs(typ, in)
+			if err != nil {
+				return err
+			}
+			ret, err := fn.CallAsFunction(this, args)
+			if err != nil {
+				return err
+			}
+			if resultType != nil {
+				if ret == nil {
+					ret = ctx.NewUndefinedValue()
+				}
+				result, err = ret.convertTo(resultType)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}()
+		if err != nil && !hasError {
+			panic(err)
+		}
+
+		var out []reflect.Value
+		if resultType != nil {
+			if err != nil {
+				result = reflect.Zero(resultType)
+			}
+			out = append(out, result)
+		}
+		if hasError {
+			errVal := reflect.Zero(errorType)
+			if err != nil {
+				errVal = reflect.ValueOf(&err).Elem()
+			}
+			out = append(out, errVal)
+		}
+		return out
+	}), nil
+}
+
+// funcArgs converts the arguments of a call to a Go function of type typ to
+// JavaScript, spreading the variadic arguments.
+func (ctx *Context) funcArgs(typ reflect.Type, in []reflect.Value) ([]*Value, error) {
+	if typ.IsVariadic() {
+		last := in[len(in)-1]
+		in = in[:len(in)-1]
+		for i := 0; i < last.Len(); i++ {
+			in = append(in, last.Index(i))
+		}
+	}
+	args := make([]*Value, len(in))
+	for i, arg := range in {
+		v, err := ctx.plainDataToJSValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		args[i] = v
+	}
+	return args, nil
+}
+
+// plainDataToJSValue is like reflectToJSValueErr, but also converts plain
+// data, structs, maps, arrays and slices other than those becoming typed
+// arrays, through its JSON representation, as BindFunc promises for the
+// arguments of bound functions.
+func (ctx *Context) plainDataToJSValue(value reflect.Value) (*Value, error) {
+	switch value.Kind() {
+	case reflect.Slice:
+		if _, ok := sliceTypedArrayType(value.Type()); ok {
+			break
+		}
+		if value.IsNil() {
+			return ctx.NewNullValue(), nil
+		}
+		return ctx.newValueFromGoJSON(value)
+	case reflect.Struct, reflect.Map, reflect.Array:
+		if value.Type() == timeType {
+			break
+		}
+		return ctx.newValueFromGoJSON(value)
+	case reflect.Interface:
+		if value.IsNil() {
+			return ctx.NewNullValue(), nil
+		}
+		return ctx.plainDataToJSValue(value.Elem())
+	}
+	return ctx.reflectToJSValueErr(value)
+}
+
+// reflectToJSValueErr is like reflectToJSValue, but returns an error for
+// values that cannot be converted instead of panicking.
+func (ctx *Context) reflectToJSValueErr(value reflect.Value) (v *Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			v, err = nil, fmt.Errorf("gojs: cannot convert %v to a JavaScript value: %v", value.Type(), r)
+		}
+	}()
+	v = ctx.reflectToJSValue(value)
+	if v == nil {
+		v = ctx.NewUndefinedValue()
+	}
+	return v, nil
+}
+
+// BindObject sets the function fields of the struct structPtr points to
+// from the methods of jsObj, adapting a JavaScript object to Go. Each field
+// is bound with BindFunc to the method of the same name, with its first
+// letter lowercased, so that a field OnStart calls jsObj.onStart with jsObj
+// as this. A field tag of the form `js:"name"` gives another name, and
+// `js:"-"` skips the field.
+//
+// Methods are required unless tagged as optional, as in
+// `js:"onEvent,optional"` or `js:",optional"`. The fields of missing
+// optional methods are set to nil, so that callers can tell them apart.
+// BindObject returns an error naming the missing required methods, or a
+// *NotAFunctionError for a property of jsObj that is not a function.
+// Fields are left unchanged if it returns an error.
+//
+// jsObj is protected from garbage collection for as long as any of the
+// bound fields is reachable.
+func (ctx *Context) BindObject(jsObj *Object, structPtr interface{}) error {
+	ptr := reflect.ValueOf(structPtr)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("gojs: BindObject needs a pointer to a struct, not %T", structPtr)
+	}
+	st := ptr.Elem()
+
+	fields := make(map[int]reflect.Value)
+	var missing []string
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Type().Field(i)
+		if field.PkgPath != "" || field.Type.Kind() != reflect.Func {
+			continue
+		}
+		name, optional, skip := parseBindTag(field)
+		if skip {
+			continue
+		}
+
+		method, err := jsObj.GetProperty(name)
+		if err != nil {
+			return err
+		}
+		if method.IsUndefined() || method.IsNull() {
+			if !optional {
+				missing = append(missing, name)
+			}
+			fields[i] = reflect.Zero(field.Type)
+			continue
+		}
+		if !method.IsFunction() {
+			return &NotAFunctionError{Name: name, Kind: method.Kind()}
+		}
+		fn, err := ctx.makeFunc(method.ToObjectOrDie(), jsObj, field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		fields[i] = fn
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("gojs: object is missing required methods %s", strings.Join(missing, ", "))
+	}
+
+	for i, fn := range fields {
+		st.Field(i).Set(fn)
+	}
+	return nil
+}
+
+// parseBindTag returns the name of the method a field is bound to by
+// BindObject, and whether it is optional or skipped.
+func parseBindTag(field reflect.StructField) (name string, optional, skip bool) {
+	tag := field.Tag.Get("js")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = strings.ToLower(field.Name[:1]) + field.Name[1:]
+	}
+	return name, opts == "optional", false
+}
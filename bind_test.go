@@ -0,0 +1,159 @@
+package gojs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type bindProps struct {
+	Title string   `json:"title"`
+	Tags  []string `json:"tags"`
+}
+
+func TestBindFunc(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript(`(function (props) {
+		if (!props.title) {
+			throw new Error("no title");
+		}
+		return "<h1>" + props.title + "</h1>" + props.tags.join(",");
+	})`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+
+	var render func(bindProps) (string, error)
+	if err := ctx.BindFunc(val.ToObjectOrDie(), &render); err != nil {
+		t.Fatalf("ctx.BindFunc failed: %v", err)
+	}
+	ctx.GarbageCollect()
+
+	html, err := render(bindProps{Title: "Hi", Tags: []string{"a", "b"}})
+	if err != nil || html != "<h1>Hi</h1>a,b" {
+		t.Errorf("render() = %q, %v, want <h1>Hi</h1>a,b", html, err)
+	}
+	if _, err := render(bindProps{}); err == nil || !strings.Contains(err.Error(), "no title") {
+		t.Errorf("render() with no title returned %v, want the exception", err)
+	}
+}
+
+func TestBindFuncResults(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	fn, err := ctx.EvaluateScript("(function () { return Array.prototype.slice.call(arguments); })", nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	jsFn := fn.ToObjectOrDie()
+
+	var sum func(...int) []int
+	if err := ctx.BindFunc(jsFn, &sum); err != nil {
+		t.Fatalf("ctx.BindFunc(sum) failed: %v", err)
+	}
+	if got := sum(1, 2, 3); len(got) != 3 || got[2] != 3 {
+		t.Errorf("sum(1, 2, 3) = %v, want [1 2 3]", got)
+	}
+
+	var wrongType func(string) (bool, error)
+	if err := ctx.BindFunc(jsFn, &wrongType); err != nil {
+		t.Fatalf("ctx.BindFunc(wrongType) failed: %v", err)
+	}
+	if _, err := wrongType("x"); err == nil {
+		t.Errorf("converting an array to bool succeeded")
+	}
+
+	var noError func() bool
+	if err := ctx.BindFunc(jsFn, &noError); err != nil {
+		t.Fatalf("ctx.BindFunc(noError) failed: %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("noError did not panic for a result of the wrong type")
+			}
+		}()
+		noError()
+	}()
+
+	var tooMany func() (int, int)
+	if err := ctx.BindFunc(jsFn, &tooMany); err == nil {
+		t.Errorf("ctx.BindFunc succeeded for a function with two non-error results")
+	}
+	if err := ctx.BindFunc(jsFn, tooMany); err == nil {
+		t.Errorf("ctx.BindFunc succeeded for a function rather than a pointer")
+	}
+	if err := ctx.BindFunc(ctx.NewEmptyObject(), &sum); err == nil {
+		t.Errorf("ctx.BindFunc succeeded for an object that is not a function")
+	}
+}
+
+type pluginHooks struct {
+	OnStart func() error
+	OnEvent func(name string, data map[string]int) (bool, error) `js:",optional"`
+	Stop    func()                                               `js:"shutdown,optional"`
+	Name    string
+	Ignored func() `js:"-"`
+}
+
+func TestBindObject(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript(`({
+		started: false,
+		onStart: function () { this.started = true; },
+		onEvent: function (name, data) { return this.started && name === "tick" && data.n === 1; }
+	})`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	plugin := val.ToObjectOrDie()
+
+	hooks := pluginHooks{Stop: func() {}}
+	if err := ctx.BindObject(plugin, &hooks); err != nil {
+		t.Fatalf("ctx.BindObject failed: %v", err)
+	}
+	if hooks.Stop != nil {
+		t.Errorf("hooks.Stop is set although the plugin has no shutdown method")
+	}
+	if hooks.Ignored != nil {
+		t.Errorf("hooks.Ignored is set although it is tagged js:\"-\"")
+	}
+	if err := hooks.OnStart(); err != nil {
+		t.Fatalf("hooks.OnStart failed: %v", err)
+	}
+	if ok, err := hooks.OnEvent("tick", map[string]int{"n": 1}); err != nil || !ok {
+		t.Errorf("hooks.OnEvent() = %v, %v, want true", ok, err)
+	}
+}
+
+func TestBindObjectErrors(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	empty := ctx.NewEmptyObject()
+	var hooks pluginHooks
+	err := ctx.BindObject(empty, &hooks)
+	if err == nil {
+		t.Fatalf("ctx.BindObject of an empty object succeeded")
+	}
+	if !strings.Contains(err.Error(), "onStart") {
+		t.Errorf("ctx.BindObject of an empty object returned %v, want an error naming onStart", err)
+	}
+	if strings.Contains(err.Error(), "onEvent") {
+		t.Errorf("ctx.BindObject reported the optional onEvent as missing: %v", err)
+	}
+
+	val, _ := ctx.EvaluateScript("({ onStart: 1 })", nil, "", 1)
+	var notFn *NotAFunctionError
+	if err := ctx.BindObject(val.ToObjectOrDie(), &hooks); !errors.As(err, &notFn) || notFn.Name != "onStart" {
+		t.Errorf("ctx.BindObject returned %v, want a NotAFunctionError for onStart", err)
+	}
+	if err := ctx.BindObject(empty, hooks); err == nil {
+		t.Errorf("ctx.BindObject succeeded for a struct rather than a pointer")
+	}
+}
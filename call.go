@@ -0,0 +1,103 @@
+package gojs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NotAFunctionError is returned when calling a value that is not a
+// function.
+type NotAFunctionError struct {
+	// Name is the name of the method or path of the value that was called,
+	// or empty if the value has no name.
+	Name string
+	// Kind is the kind of the value that was called.
+	Kind Kind
+}
+
+func (e *NotAFunctionError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("gojs: %v is not a function", e.Kind)
+	}
+	return fmt.Sprintf("gojs: %s is not a function (it is %v)", e.Name, e.Kind)
+}
+
+// ValueOf converts the Go value x to a JavaScript value, as the results of
+// native functions are converted. *Value and *Object are returned as they
+// are, and nil becomes null.
+func (ctx *Context) ValueOf(x interface{}) (*Value, error) {
+	if x == nil {
+		return ctx.NewNullValue(), nil
+	}
+	return ctx.reflectToJSValueErr(reflect.ValueOf(x))
+}
+
+// valuesOf converts args with ValueOf.
+func (ctx *Context) valuesOf(args []interface{}) ([]*Value, error) {
+	values := make([]*Value, len(args))
+	for i, arg := range args {
+		v, err := ctx.ValueOf(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// Call calls the method of obj named method with args, converted with
+// ValueOf, and obj as this. It returns a *NotAFunctionError if obj has no
+// such method.
+func (obj *Object) Call(method string, args ...interface{}) (*Value, error) {
+	fn, err := obj.GetProperty(method)
+	if err != nil {
+		return nil, err
+	}
+	if !fn.IsFunction() {
+		return nil, &NotAFunctionError{Name: method, Kind: fn.Kind()}
+	}
+	values, err := obj.ctx.valuesOf(args)
+	if err != nil {
+		return nil, err
+	}
+	return fn.ToObjectOrDie().CallAsFunction(obj, values)
+}
+
+// Invoke calls the function fn with args, converted with ValueOf, and this
+// as this. If this is nil, the function is called as a plain function call
+// would. It returns a *NotAFunctionError if fn is not a function.
+func (fn *Object) Invoke(this *Object, args ...interface{}) (*Value, error) {
+	if !fn.IsFunction() {
+		return nil, &NotAFunctionError{Kind: KindObject}
+	}
+	values, err := fn.ctx.valuesOf(args)
+	if err != nil {
+		return nil, err
+	}
+	return fn.CallAsFunction(this, values)
+}
+
+// Global returns the value at the dotted path from the global object, such
+// as "JSON.stringify" or "app.config.version". Missing properties at the end
+// of the path are undefined; it is an error for any other part of the path
+// to be undefined or null.
+func (ctx *Context) Global(path string) (*Value, error) {
+	v := ctx.GlobalObject().ToValue()
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		if v.IsUndefined() || v.IsNull() {
+			return nil, fmt.Errorf("gojs: cannot read %s of %s, which is %v",
+				name, strings.Join(names[:i], "."), v.Kind())
+		}
+		obj, err := v.ToObject()
+		if err != nil {
+			return nil, err
+		}
+		v, err = obj.GetProperty(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
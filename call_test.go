@@ -0,0 +1,93 @@
+package gojs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestObject_Call(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript(`({
+		name: "view",
+		render: function (props, n, flag) {
+			return this.name + ":" + props.title + ":" + (n + 1) + ":" + flag;
+		}
+	})`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	obj := val.ToObjectOrDie()
+
+	props, _ := ctx.NewObjectWithProperties(map[string]*Value{"title": ctx.NewStringValue("Hi")})
+	ret, err := obj.Call("render", props, 41, true)
+	if err != nil {
+		t.Fatalf("obj.Call failed: %v", err)
+	}
+	if s := ret.String(); s != "view:Hi:42:true" {
+		t.Errorf("obj.Call(render) = %s, want view:Hi:42:true", s)
+	}
+
+	_, err = obj.Call("name")
+	var notFn *NotAFunctionError
+	if !errors.As(err, &notFn) || notFn.Name != "name" || notFn.Kind != KindString {
+		t.Errorf("obj.Call(name) returned %v, want a NotAFunctionError for a string", err)
+	}
+	if _, err := obj.Call("missing"); !errors.As(err, &notFn) || notFn.Kind != KindUndefined {
+		t.Errorf("obj.Call(missing) returned %v, want a NotAFunctionError for undefined", err)
+	}
+	if _, err := obj.Call("render", struct{}{}); err == nil {
+		t.Errorf("obj.Call with an argument that cannot be converted succeeded")
+	}
+}
+
+func TestObject_Invoke(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript("(function (a, b) { return (this.prefix || '') + a + b; })", nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	fn := val.ToObjectOrDie()
+
+	this, _ := ctx.NewObjectWithProperties(map[string]*Value{"prefix": ctx.NewStringValue(">")})
+	if ret, err := fn.Invoke(this, "a", nil); err != nil || ret.String() != ">anull" {
+		t.Errorf("fn.Invoke(this) = %v, %v, want >anull", ret, err)
+	}
+	if ret, err := fn.Invoke(nil, 1, 2.5); err != nil || ret.String() != "3.5" {
+		t.Errorf("fn.Invoke(nil) = %v, %v, want 3.5", ret, err)
+	}
+
+	var notFn *NotAFunctionError
+	if _, err := ctx.NewEmptyObject().Invoke(nil); !errors.As(err, &notFn) {
+		t.Errorf("Invoke of a plain object returned %v, want a NotAFunctionError", err)
+	}
+}
+
+func TestContext_Global(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	if _, err := ctx.EvaluateScript("var app = { config: { version: '1.2' } }", nil, "", 1); err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	if v, err := ctx.Global("app.config.version"); err != nil || v.String() != "1.2" {
+		t.Errorf("ctx.Global(app.config.version) = %v, %v, want 1.2", v, err)
+	}
+	if v, err := ctx.Global("app.config.missing"); err != nil || !v.IsUndefined() {
+		t.Errorf("ctx.Global(app.config.missing) = %v, %v, want undefined", v, err)
+	}
+	if v, err := ctx.Global("app.config.version.length"); err != nil || v.String() != "3" {
+		t.Errorf("ctx.Global(app.config.version.length) = %v, %v, want 3", v, err)
+	}
+	if _, err := ctx.Global("app.nothing.version"); err == nil {
+		t.Errorf("ctx.Global(app.nothing.version) succeeded")
+	}
+
+	stringify, err := ctx.Global("JSON.stringify")
+	if err != nil || !stringify.IsFunction() {
+		t.Fatalf("ctx.Global(JSON.stringify) = %v, %v, want a function", stringify, err)
+	}
+}
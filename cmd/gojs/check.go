@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"sync"
+
+	"github.com/crazy2be/gojs"
+)
+
+// checkResult is the outcome of checking one file. In JSON output, Line and
+// Column are zero for files that could not be read.
+type checkResult struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (r checkResult) String() string {
+	if r.Line == 0 {
+		return r.File + ": " + r.Message
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", r.File, r.Line, r.Column, r.Message)
+}
+
+// expandPatterns returns the files named by args, in order and without
+// duplicates. Arguments may be files, directories, whose .js files are
+// checked recursively, or glob patterns.
+func expandPatterns(args []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(file string) {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, arg := range args {
+		matches := []string{arg}
+		if strings.ContainsAny(arg, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(arg); err != nil {
+				return nil, fmt.Errorf("bad pattern %q: %v", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %q", arg)
+			}
+		}
+
+		for _, match := range matches {
+			fi, err := os.Stat(match)
+			if err != nil || !fi.IsDir() {
+				// Unreadable files are reported by checkFile.
+				add(match)
+				continue
+			}
+			err = filepath.Walk(match, func(path string, fi os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if fi.Mode().IsRegular() && strings.HasSuffix(path, ".js") {
+					add(path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+// checkFile checks the syntax of file in ctx, returning nil if it is valid.
+func checkFile(ctx *gojs.Context, file string) *checkResult {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		msg := err.Error()
+		var perr *os.PathError
+		if errors.As(err, &perr) {
+			msg = perr.Err.Error()
+		}
+		return &checkResult{File: file, Message: msg}
+	}
+	src := string(data)
+	if strings.HasPrefix(src, "#!") {
+		src = "//" + src[2:]
+	}
+
+	err = ctx.CheckScriptSyntax(src, file, 1)
+	if err == nil {
+		return nil
+	}
+	result := &checkResult{File: file, Message: err.Error()}
+	if serr, ok := err.(*gojs.SyntaxError); ok {
+		result.Line, result.Column = serr.Line, serr.Column
+	}
+	return result
+}
+
+// checkFiles checks files on up to jobs goroutines, each with a context of
+// its own, and returns the failures in the order of files.
+func checkFiles(files []string, jobs int) []checkResult {
+	results := make([]*checkResult, len(files))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			goruntime.LockOSThread()
+			defer goruntime.UnlockOSThread()
+			ctx := gojs.NewContext()
+			defer ctx.Release()
+			for i := range indexes {
+				results[i] = checkFile(ctx, files[i])
+			}
+		}()
+	}
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var failed []checkResult
+	for _, r := range results {
+		if r != nil {
+			failed = append(failed, *r)
+		}
+	}
+	return failed
+}
+
+// writeCheckResults prints the failures one per line, or as a JSON array.
+func writeCheckResults(w io.Writer, failed []checkResult, asJSON bool) error {
+	if !asJSON {
+		for _, r := range failed {
+			if _, err := fmt.Fprintln(w, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if failed == nil {
+		failed = []checkResult{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(failed)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestExpandPatterns(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.js":           "",
+		"b.js":           "",
+		"notes.txt":      "",
+		"rules/x.js":     "",
+		"rules/y.json":   "",
+		"rules/sub/z.js": "",
+	})
+	join := func(names ...string) []string {
+		for i, name := range names {
+			names[i] = filepath.Join(dir, name)
+		}
+		return names
+	}
+
+	files, err := expandPatterns(join("b.js", "*.js", "rules", "missing.js"))
+	if err != nil {
+		t.Fatalf("expandPatterns failed: %v", err)
+	}
+	if want := join("b.js", "a.js", "rules/sub/z.js", "rules/x.js", "missing.js"); !reflect.DeepEqual(files, want) {
+		t.Errorf("expandPatterns returned %q, want %q", files, want)
+	}
+
+	if _, err := expandPatterns(join("*.ts")); err == nil {
+		t.Errorf("expandPatterns did not fail for a pattern matching nothing")
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"good.js":  "#!/usr/bin/env gojs\nvar a = 1;",
+		"bad.js":   "var a = 1;\nvar b = ;",
+		"worse.js": "function (",
+		"good2.js": "a = [1, 2, 3].map(function (x) { return x; });",
+	})
+	files, err := expandPatterns([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.js")
+	files = append(files, missing)
+
+	failed := checkFiles(files, 2)
+	if len(failed) != 3 {
+		t.Fatalf("checkFiles reported %d failures, want 3: %v", len(failed), failed)
+	}
+	bad := failed[0]
+	if bad.File != filepath.Join(dir, "bad.js") || bad.Line != 2 || bad.Column == 0 || bad.Message == "" {
+		t.Errorf("bad.js reported as %+v", bad)
+	}
+	if failed[1].File != filepath.Join(dir, "worse.js") {
+		t.Errorf("second failure is %+v, want worse.js", failed[1])
+	}
+	if failed[2].File != missing || failed[2].Line != 0 {
+		t.Errorf("missing file reported as %+v", failed[2])
+	}
+}
+
+func TestWriteCheckResults(t *testing.T) {
+	failed := []checkResult{
+		{File: "a.js", Line: 2, Column: 9, Message: "SyntaxError: Unexpected token ';'"},
+		{File: "b.js", Message: "no such file or directory"},
+	}
+
+	var out bytes.Buffer
+	writeCheckResults(&out, failed, false)
+	if want := "a.js:2:9: SyntaxError: Unexpected token ';'\nb.js: no such file or directory\n"; out.String() != want {
+		t.Errorf("text output is %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	writeCheckResults(&out, failed[:1], true)
+	want := `[
+  {
+    "file": "a.js",
+    "line": 2,
+    "column": 9,
+    "message": "SyntaxError: Unexpected token ';'"
+  }
+]
+`
+	if out.String() != want {
+		t.Errorf("JSON output is %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	writeCheckResults(&out, nil, true)
+	if out.String() != "[]\n" {
+		t.Errorf("JSON output without failures is %q, want []", out.String())
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/crazy2be/gojs"
+)
+
+// installConsole defines the global console object, writing log, info and
+// debug messages to stdout and warn and error messages to stderr.
+func installConsole(ctx *gojs.Context, in *inspector, stdout, stderr io.Writer) error {
+	print := func(w io.Writer) *gojs.Value {
+		return ctx.NewFunctionWithCallback(func(ctx *gojs.Context, _, _ *gojs.Object, args []*gojs.Value) *gojs.Value {
+			fmt.Fprintln(w, formatArgs(in, args))
+			return ctx.NewUndefinedValue()
+		}).ToValue()
+	}
+	console, err := ctx.NewObjectWithProperties(map[string]*gojs.Value{
+		"log":   print(stdout),
+		"info":  print(stdout),
+		"debug": print(stdout),
+		"warn":  print(stderr),
+		"error": print(stderr),
+	})
+	if err != nil {
+		return err
+	}
+	return ctx.GlobalObject().SetProperty("console", console.ToValue(), 0)
+}
+
+// formatArgs formats the arguments of a console call. If the first one is
+// a string it may contain the verbs %s, %d, %i, %f, %j, %o, %O and %%, each
+// replaced by the next argument. Remaining arguments are appended, strings
+// as they are and other values inspected.
+func formatArgs(in *inspector, args []*gojs.Value) string {
+	var parts []string
+	if len(args) > 0 && args[0].IsString() {
+		var format string
+		format, args = formatVerbs(in, args[0].ToStringOrDie(), args[1:])
+		parts = append(parts, format)
+	}
+	for _, arg := range args {
+		if arg.IsString() {
+			parts = append(parts, arg.ToStringOrDie())
+		} else {
+			parts = append(parts, in.inspect(arg))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatVerbs replaces the verbs in format and returns the unused arguments.
+func formatVerbs(in *inspector, format string, args []*gojs.Value) (string, []*gojs.Value) {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' || i+1 == len(format) {
+			b.WriteByte(c)
+			continue
+		}
+		verb := format[i+1]
+		if verb == '%' {
+			b.WriteByte('%')
+			i++
+			continue
+		}
+		if !strings.ContainsRune("sdifjoO", rune(verb)) || len(args) == 0 {
+			b.WriteByte(c)
+			continue
+		}
+		arg := args[0]
+		args = args[1:]
+		i++
+
+		switch verb {
+		case 's':
+			if arg.IsString() {
+				b.WriteString(arg.ToStringOrDie())
+			} else if arg.IsObject() {
+				b.WriteString(in.inspect(arg))
+			} else {
+				b.WriteString(in.stringOf(arg))
+			}
+		case 'd', 'i', 'f':
+			n, err := arg.ToNumber()
+			if err != nil {
+				n = math.NaN()
+			}
+			if verb == 'i' {
+				n = math.Trunc(n)
+			}
+			b.WriteString(in.stringOf(in.ctx.NewNumberValue(n)))
+		case 'j':
+			if arg.IsUndefined() || arg.IsObject() && arg.ToObjectOrDie().IsFunction() {
+				// JSON.stringify returns undefined for these.
+				b.WriteString("undefined")
+				break
+			}
+			json, err := arg.ToJSON()
+			if err != nil {
+				b.WriteString("[Circular]")
+			} else {
+				b.Write(json)
+			}
+		case 'o', 'O':
+			b.WriteString(in.inspect(arg))
+		}
+	}
+	return b.String(), args
+}
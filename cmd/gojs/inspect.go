@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/crazy2be/gojs"
+)
+
+const (
+	inspectDepth    = 2   // levels of nested objects to expand
+	inspectMaxItems = 100 // array elements to show
+	inspectWidth    = 72  // longest object or array kept on one line
+)
+
+// inspector formats JavaScript values for display, expanding nested objects
+// and arrays.
+type inspector struct {
+	ctx      *gojs.Context
+	toString *gojs.Object // Object.prototype.toString, for the class of objects
+	str      *gojs.Object // the String function, for primitives
+	seen     []*gojs.Value
+}
+
+func newInspector(ctx *gojs.Context) (*inspector, error) {
+	toString, err := ctx.EvaluateScript("Object.prototype.toString", nil, "", 1)
+	if err != nil {
+		return nil, err
+	}
+	str, err := ctx.EvaluateScript("String", nil, "", 1)
+	if err != nil {
+		return nil, err
+	}
+	// Scripts may replace the globals these came from, so they are kept
+	// from the garbage collector.
+	toString.Protect()
+	str.Protect()
+	return &inspector{
+		ctx:      ctx,
+		toString: toString.ToObjectOrDie(),
+		str:      str.ToObjectOrDie(),
+	}, nil
+}
+
+// inspect returns a string representation of v.
+func (in *inspector) inspect(v *gojs.Value) string {
+	in.seen = in.seen[:0]
+	return in.format(v, 0)
+}
+
+func (in *inspector) format(v *gojs.Value, depth int) string {
+	switch v.Kind() {
+	case gojs.KindString:
+		return strconv.Quote(v.ToStringOrDie())
+	case gojs.KindBigInt:
+		return in.stringOf(v) + "n"
+	case gojs.KindObject:
+		return in.formatObject(v, depth)
+	}
+	return in.stringOf(v)
+}
+
+// stringOf converts v with String(), which unlike ToString also accepts
+// symbols.
+func (in *inspector) stringOf(v *gojs.Value) string {
+	ret, err := in.str.CallAsFunction(in.ctx.GlobalObject(), []*gojs.Value{v})
+	if err != nil {
+		return "[" + err.Error() + "]"
+	}
+	return ret.ToStringOrDie()
+}
+
+func (in *inspector) class(obj *gojs.Object) string {
+	ret, err := in.toString.CallAsFunction(obj, nil)
+	if err != nil {
+		return "Object"
+	}
+	tag := ret.ToStringOrDie()
+	return strings.TrimSuffix(strings.TrimPrefix(tag, "[object "), "]")
+}
+
+func (in *inspector) formatObject(v *gojs.Value, depth int) string {
+	obj := v.ToObjectOrDie()
+	class := in.class(obj)
+
+	if v.IsFunction() {
+		name, _ := obj.GetProperty("name")
+		if name == nil || !name.IsString() || name.ToStringOrDie() == "" {
+			return "[Function (anonymous)]"
+		}
+		return "[Function: " + name.ToStringOrDie() + "]"
+	}
+	if v.IsDate() || v.IsError() || class == "RegExp" || class == "Symbol" {
+		return in.stringOf(v)
+	}
+
+	for _, seen := range in.seen {
+		if seen.Equals(v) {
+			return "[Circular]"
+		}
+	}
+	isArray := v.IsArray()
+	if depth > inspectDepth {
+		if isArray {
+			return "[Array]"
+		}
+		return "[Object]"
+	}
+	in.seen = append(in.seen, v)
+	defer func() { in.seen = in.seen[:len(in.seen)-1] }()
+
+	if isArray {
+		return in.formatArray(obj, depth)
+	}
+
+	var parts []string
+	names := obj.CopyPropertyNames()
+	defer names.Release()
+	for i := 0; i < names.Count(); i++ {
+		name := names.NameAtIndex(i)
+		parts = append(parts, formatKey(name)+": "+in.property(obj, name, depth))
+	}
+
+	prefix := ""
+	if ctor := in.constructorName(obj); ctor != "" && ctor != "Object" {
+		prefix = ctor + " "
+	} else if class != "Object" {
+		prefix = class + " "
+	}
+	if len(parts) == 0 {
+		return prefix + "{}"
+	}
+	return prefix + wrapParts("{", parts, "}", depth)
+}
+
+func (in *inspector) formatArray(obj *gojs.Object, depth int) string {
+	length, _ := obj.Len()
+
+	var parts []string
+	for i := 0; i < length && i < inspectMaxItems; i++ {
+		elem, err := obj.At(i)
+		if err != nil {
+			parts = append(parts, "[Error: "+err.Error()+"]")
+			continue
+		}
+		parts = append(parts, in.format(elem, depth+1))
+	}
+	if length > inspectMaxItems {
+		parts = append(parts, fmt.Sprintf("... %d more items", length-inspectMaxItems))
+	}
+	if len(parts) == 0 {
+		return "[]"
+	}
+	return wrapParts("[", parts, "]", depth)
+}
+
+func (in *inspector) property(obj *gojs.Object, name string, depth int) string {
+	val, err := obj.GetProperty(name)
+	if err != nil {
+		return "[Error: " + err.Error() + "]"
+	}
+	return in.format(val, depth+1)
+}
+
+func (in *inspector) constructorName(obj *gojs.Object) string {
+	ctor, err := obj.GetProperty("constructor")
+	if err != nil || !ctor.IsObject() {
+		return ""
+	}
+	name, err := ctor.ToObjectOrDie().GetProperty("name")
+	if err != nil || !name.IsString() {
+		return ""
+	}
+	return name.ToStringOrDie()
+}
+
+// wrapParts joins the formatted members of an object or array, on one line if
+// they are short enough and one per line otherwise.
+func wrapParts(open string, parts []string, close string, depth int) string {
+	line := open + " " + strings.Join(parts, ", ") + " " + close
+	if len(line) <= inspectWidth && !strings.Contains(line, "\n") {
+		return line
+	}
+	indent := strings.Repeat("  ", depth+1)
+	return open + "\n" + indent + strings.Join(parts, ",\n"+indent) + "\n" + strings.Repeat("  ", depth) + close
+}
+
+// formatKey quotes property names that are not identifiers.
+func formatKey(name string) string {
+	if isIdentifier(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 0x7f ||
+			i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// errInterrupted is returned by readLine when the user presses Ctrl-C.
+var errInterrupted = errors.New("interrupted")
+
+// completer returns the replacements for the word ending at pos in line,
+// along with the length of that word.
+type completer func(line []rune, pos int) (wordLen int, candidates []string)
+
+// lineEditor reads lines from a terminal in raw mode, with cursor movement,
+// history recall and tab completion. It writes ANSI escape sequences to out
+// and assumes the line fits on one terminal row.
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	complete completer
+	// raw, if set, puts the terminal into raw mode while a line is read
+	// and returns a function restoring the previous mode.
+	raw func() (restore func(), err error)
+
+	history     []string
+	historyFile string
+	historySize int
+}
+
+func newLineEditor(in io.Reader, out io.Writer) *lineEditor {
+	return &lineEditor{
+		in:          bufio.NewReader(in),
+		out:         out,
+		historySize: 1000,
+	}
+}
+
+// loadHistory reads the history saved in path and appends new entries to it
+// from then on.
+func (e *lineEditor) loadHistory(path string) error {
+	e.historyFile = path
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			e.history = append(e.history, line)
+		}
+	}
+	if len(e.history) > e.historySize {
+		e.history = e.history[len(e.history)-e.historySize:]
+	}
+	return nil
+}
+
+func (e *lineEditor) addHistory(line string) {
+	if line == "" || len(e.history) > 0 && e.history[len(e.history)-1] == line {
+		return
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > e.historySize {
+		e.history = e.history[1:]
+	}
+	if e.historyFile == "" {
+		return
+	}
+	f, err := os.OpenFile(e.historyFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(f, line)
+	f.Close()
+}
+
+// readLine reads a line, showing prompt in front of it. It returns io.EOF
+// when Ctrl-D is pressed on an empty line and errInterrupted on Ctrl-C.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	if e.raw != nil {
+		restore, err := e.raw()
+		if err != nil {
+			return "", err
+		}
+		defer restore()
+	}
+
+	var (
+		line    []rune
+		pos     int
+		recall  = len(e.history)
+		pending []rune // the line being edited while browsing history
+	)
+
+	redraw := func() {
+		fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(line))
+		if n := len(line) - pos; n > 0 {
+			fmt.Fprintf(e.out, "\x1b[%dD", n)
+		}
+	}
+	insert := func(rs ...rune) {
+		line = append(line[:pos], append(rs, line[pos:]...)...)
+		pos += len(rs)
+	}
+
+	fmt.Fprint(e.out, prompt)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(line), nil
+		case 3: // Ctrl-C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case 4: // Ctrl-D
+			if len(line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if pos < len(line) {
+				line = append(line[:pos], line[pos+1:]...)
+			}
+		case 1: // Ctrl-A
+			pos = 0
+		case 5: // Ctrl-E
+			pos = len(line)
+		case 2: // Ctrl-B
+			if pos > 0 {
+				pos--
+			}
+		case 6: // Ctrl-F
+			if pos < len(line) {
+				pos++
+			}
+		case 11: // Ctrl-K
+			line = line[:pos]
+		case 21: // Ctrl-U
+			line = line[pos:]
+			pos = 0
+		case 12: // Ctrl-L
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case 127, 8: // Backspace
+			if pos > 0 {
+				line = append(line[:pos-1], line[pos:]...)
+				pos--
+			}
+		case '\t':
+			e.completeWord(&line, &pos)
+		case 16, 14: // Ctrl-P, Ctrl-N
+			recall, pending = e.recall(r == 16, recall, pending, &line, &pos)
+		case 27: // Escape sequence
+			seq := e.readEscape()
+			switch seq {
+			case "[A", "OA":
+				recall, pending = e.recall(true, recall, pending, &line, &pos)
+			case "[B", "OB":
+				recall, pending = e.recall(false, recall, pending, &line, &pos)
+			case "[C", "OC":
+				if pos < len(line) {
+					pos++
+				}
+			case "[D", "OD":
+				if pos > 0 {
+					pos--
+				}
+			case "[H", "OH", "[1~", "[7~":
+				pos = 0
+			case "[F", "OF", "[4~", "[8~":
+				pos = len(line)
+			case "[3~":
+				if pos < len(line) {
+					line = append(line[:pos], line[pos+1:]...)
+				}
+			}
+		default:
+			if r >= ' ' && r != utf8.RuneError {
+				insert(r)
+			}
+		}
+		redraw()
+	}
+}
+
+// readEscape reads the rest of an escape sequence after the escape byte.
+func (e *lineEditor) readEscape() string {
+	var seq []byte
+	for {
+		b, err := e.in.ReadByte()
+		if err != nil {
+			return string(seq)
+		}
+		seq = append(seq, b)
+		// Sequences end with a letter or a tilde, after the introducer.
+		if len(seq) > 1 && (b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z' || b == '~') {
+			return string(seq)
+		}
+		if len(seq) == 1 && b != '[' && b != 'O' {
+			return string(seq)
+		}
+	}
+}
+
+// recall moves through the history, returning the new history index and the
+// line that was being edited before browsing started.
+func (e *lineEditor) recall(back bool, index int, pending []rune, line *[]rune, pos *int) (int, []rune) {
+	if index == len(e.history) {
+		pending = append([]rune(nil), *line...)
+	}
+	if back && index > 0 {
+		index--
+	} else if !back && index < len(e.history) {
+		index++
+	} else {
+		return index, pending
+	}
+
+	if index == len(e.history) {
+		*line = append([]rune(nil), pending...)
+	} else {
+		*line = []rune(e.history[index])
+	}
+	*pos = len(*line)
+	return index, pending
+}
+
+// completeWord completes the word before the cursor. When there are several
+// candidates it inserts their common prefix, or lists them if there is none
+// to insert.
+func (e *lineEditor) completeWord(line *[]rune, pos *int) {
+	if e.complete == nil {
+		return
+	}
+	wordLen, candidates := e.complete(*line, *pos)
+	if len(candidates) == 0 {
+		return
+	}
+
+	prefix := candidates[0]
+	for _, c := range candidates[1:] {
+		for !strings.HasPrefix(c, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+	word := string((*line)[*pos-wordLen : *pos])
+	if len(candidates) > 1 && prefix == word {
+		fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+		return
+	}
+
+	rest := append([]rune(prefix), (*line)[*pos:]...)
+	*line = append((*line)[:*pos-wordLen], rest...)
+	*pos += utf8.RuneCountInString(prefix) - wordLen
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadLine(t *testing.T) {
+	tests := []struct {
+		keys, want string
+	}{
+		{"abc\r", "abc"},
+		{"abd\x7fc\r", "abc"},
+		{"bc\x01a\r", "abc"},
+		{"ac\x1b[Db\r", "abc"},
+		{"abcdef\x1b[D\x1b[D\x1b[D\x0b\r", "abc"},
+		{"xyzabc\x1b[D\x1b[D\x1b[D\x15\x05\r", "abc"},
+		{"abxc\x1b[D\x1b[D\x1b[3~\r", "abc"},
+		{"héllo\x02\x02\x7f\r", "hélo"},
+	}
+	for _, test := range tests {
+		e := newLineEditor(strings.NewReader(test.keys), io.Discard)
+		got, err := e.readLine("> ")
+		if err != nil {
+			t.Errorf("readLine(%q) failed: %v", test.keys, err)
+		} else if got != test.want {
+			t.Errorf("readLine(%q) = %q, want %q", test.keys, got, test.want)
+		}
+	}
+}
+
+func TestReadLineControl(t *testing.T) {
+	e := newLineEditor(strings.NewReader("\x04"), io.Discard)
+	if _, err := e.readLine("> "); err != io.EOF {
+		t.Errorf("Ctrl-D on an empty line returned %v, want io.EOF", err)
+	}
+	e = newLineEditor(strings.NewReader("abc\x03"), io.Discard)
+	if _, err := e.readLine("> "); err != errInterrupted {
+		t.Errorf("Ctrl-C returned %v, want errInterrupted", err)
+	}
+}
+
+func TestReadLineRawMode(t *testing.T) {
+	e := newLineEditor(strings.NewReader("a\rb\r"), io.Discard)
+	raw := 0
+	e.raw = func() (func(), error) {
+		raw++
+		return func() { raw-- }, nil
+	}
+	for _, want := range []string{"a", "b"} {
+		if got, err := e.readLine("> "); err != nil || got != want {
+			t.Errorf("readLine() = %q, %v, want %q", got, err, want)
+		}
+		if raw != 0 {
+			t.Errorf("the terminal is still raw after reading %q", want)
+		}
+	}
+}
+
+func TestReadLineHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	if err := os.WriteFile(path, []byte("first\nsecond\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	e := newLineEditor(strings.NewReader("\x1b[A\x1b[A\rnew\x1b[A\x1b[B\r"), io.Discard)
+	if err := e.loadHistory(path); err != nil {
+		t.Fatalf("loadHistory failed: %v", err)
+	}
+	for _, want := range []string{"first", "new"} {
+		got, err := e.readLine("> ")
+		if err != nil {
+			t.Fatalf("readLine failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("readLine() = %q, want %q", got, want)
+		}
+		e.addHistory(got)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "first\nsecond\nfirst\nnew\n"; string(data) != want {
+		t.Errorf("history file is %q, want %q", data, want)
+	}
+}
+
+func TestReadLineComplete(t *testing.T) {
+	words := []string{"Math", "Map", "Number"}
+	complete := func(line []rune, pos int) (int, []string) {
+		start := strings.LastIndex(string(line[:pos]), " ") + 1
+		word := string(line[start:pos])
+		var candidates []string
+		for _, w := range words {
+			if strings.HasPrefix(w, word) {
+				candidates = append(candidates, w)
+			}
+		}
+		return len([]rune(word)), candidates
+	}
+
+	tests := []struct {
+		keys, want, listed string
+	}{
+		{"x = N\t\r", "x = Number", ""},
+		{"Mat\t.\r", "Math.", ""},
+		{"M\t\r", "Ma", ""},
+		{"Ma\t\r", "Ma", "Math  Map"},
+		{"Z\t\r", "Z", ""},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		e := newLineEditor(strings.NewReader(test.keys), &out)
+		e.complete = complete
+		got, err := e.readLine("> ")
+		if err != nil {
+			t.Fatalf("readLine(%q) failed: %v", test.keys, err)
+		}
+		if got != test.want {
+			t.Errorf("readLine(%q) = %q, want %q", test.keys, got, test.want)
+		}
+		if listed := strings.Contains(out.String(), "\r\nMath  Map\r\n"); listed != (test.listed != "") {
+			t.Errorf("readLine(%q) listed candidates: %v, want %v", test.keys, listed, !listed)
+		}
+	}
+}
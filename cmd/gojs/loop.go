@@ -0,0 +1,147 @@
+package main
+
+import (
+	"container/heap"
+	"time"
+)
+
+// timer is a pending timeout or interval.
+type timer struct {
+	id       int
+	when     time.Time
+	interval time.Duration // zero for timeouts
+	seq      int           // orders timers due at the same time
+	index    int           // position in the queue
+}
+
+// timerQueue is a heap of timers ordered by when they are due.
+type timerQueue []*timer
+
+func (q timerQueue) Len() int { return len(q) }
+
+func (q timerQueue) Less(i, j int) bool {
+	if q[i].when.Equal(q[j].when) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].when.Before(q[j].when)
+}
+
+func (q timerQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *timerQueue) Push(x interface{}) {
+	t := x.(*timer)
+	t.index = len(*q)
+	*q = append(*q, t)
+}
+
+func (q *timerQueue) Pop() interface{} {
+	old := *q
+	t := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return t
+}
+
+// eventLoop runs timer callbacks one at a time, in the order they are due,
+// on the goroutine calling run. It only tracks timer ids; the callbacks
+// themselves stay on the JavaScript side, out of reach of the garbage
+// collector until the timer is cleared or has fired.
+type eventLoop struct {
+	queue  timerQueue
+	timers map[int]*timer
+	lastID int
+	seq    int
+	wake   chan struct{}
+	now    func() time.Time
+}
+
+func newEventLoop() *eventLoop {
+	return &eventLoop{
+		timers: make(map[int]*timer),
+		wake:   make(chan struct{}, 1),
+		now:    time.Now,
+	}
+}
+
+// schedule adds a timer due after delay, repeating every delay if repeat is
+// set, and returns its id.
+func (l *eventLoop) schedule(delay time.Duration, repeat bool) int {
+	if delay < 0 {
+		delay = 0
+	}
+	l.lastID++
+	t := &timer{id: l.lastID, when: l.now().Add(delay)}
+	if repeat {
+		// Intervals of zero would never let the loop wait.
+		t.interval = delay
+		if t.interval < time.Millisecond {
+			t.interval = time.Millisecond
+		}
+	}
+	l.push(t)
+	l.timers[t.id] = t
+	return t.id
+}
+
+func (l *eventLoop) push(t *timer) {
+	l.seq++
+	t.seq = l.seq
+	heap.Push(&l.queue, t)
+}
+
+// cancel removes the timer id, if it is still pending.
+func (l *eventLoop) cancel(id int) {
+	t, ok := l.timers[id]
+	if !ok {
+		return
+	}
+	delete(l.timers, id)
+	heap.Remove(&l.queue, t.index)
+}
+
+// pending returns the number of timers left.
+func (l *eventLoop) pending() int {
+	return len(l.queue)
+}
+
+// wakeUp makes a waiting run check done again. It may be called from any
+// goroutine.
+func (l *eventLoop) wakeUp() {
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run calls fire for each timer as it becomes due, until no timers are left
+// or done returns true. fire may schedule and cancel timers.
+func (l *eventLoop) run(fire func(id int), done func() bool) {
+	for len(l.queue) > 0 && !done() {
+		t := l.queue[0]
+		if wait := t.when.Sub(l.now()); wait > 0 {
+			sleep := time.NewTimer(wait)
+			select {
+			case <-sleep.C:
+			case <-l.wake:
+				sleep.Stop()
+			}
+			continue
+		}
+
+		heap.Pop(&l.queue)
+		if t.interval > 0 {
+			t.when = t.when.Add(t.interval)
+			if now := l.now(); t.when.Before(now) {
+				// Skip the runs missed while a callback was busy.
+				t.when = now
+			}
+			l.push(t)
+		} else {
+			delete(l.timers, t.id)
+		}
+		fire(t.id)
+	}
+}
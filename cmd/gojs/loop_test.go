@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventLoop(t *testing.T) {
+	l := newEventLoop()
+	late := l.schedule(20*time.Millisecond, false)
+	first := l.schedule(0, false)
+	middle := l.schedule(10*time.Millisecond, false)
+	second := l.schedule(0, false)
+	l.cancel(l.schedule(5*time.Millisecond, false))
+
+	var fired []int
+	l.run(func(id int) {
+		fired = append(fired, id)
+	}, func() bool { return false })
+
+	if want := []int{first, second, middle, late}; !reflect.DeepEqual(fired, want) {
+		t.Errorf("timers fired in order %v, want %v", fired, want)
+	}
+	if n := l.pending(); n != 0 {
+		t.Errorf("%d timers pending after run, want 0", n)
+	}
+}
+
+func TestEventLoopInterval(t *testing.T) {
+	l := newEventLoop()
+	runs := 0
+	id := l.schedule(time.Millisecond, true)
+	l.run(func(fired int) {
+		if fired != id {
+			t.Fatalf("fired timer %d, want %d", fired, id)
+		}
+		if runs++; runs == 3 {
+			l.cancel(id)
+		}
+	}, func() bool { return false })
+	if runs != 3 {
+		t.Errorf("interval ran %d times, want 3", runs)
+	}
+}
+
+func TestEventLoopDone(t *testing.T) {
+	l := newEventLoop()
+	l.schedule(time.Hour, false)
+	var stopped atomic.Bool
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		stopped.Store(true)
+		l.wakeUp()
+	}()
+
+	done := make(chan bool)
+	go func() {
+		l.run(func(int) { t.Error("timer fired") }, stopped.Load)
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not return after wakeUp")
+	}
+}
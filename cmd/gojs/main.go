@@ -0,0 +1,166 @@
+// Command gojs evaluates JavaScript with JavaScriptCore, through the gojs
+// bindings.
+//
+// Usage:
+//
+//	gojs
+//	gojs run file.js [arguments]
+//	gojs check [-json] [-j n] file|dir|pattern...
+//
+// Run without arguments, gojs starts an interactive session:
+//
+//	$ gojs
+//	> [1, 2, 3].map(function (x) { return x * 2; })
+//	[ 2, 4, 6 ]
+//
+// Each entry is evaluated once it forms complete statements; an empty line
+// evaluates an incomplete entry as is. The value of the last entry is
+// available as _, and console is defined as for run. When standard input is
+// a terminal, lines can be edited, Tab completes global names and
+// properties, and history is saved to ~/.gojs_history, or to the file named
+// by $GOJS_HISTORY (set it to the empty string to disable saving).
+//
+// Run evaluates file.js as a CommonJS module, with console, the timer
+// functions (setTimeout, setInterval, setImmediate and their clear
+// counterparts), require and process defined. require loads .js and .json
+// files, directories with a package.json or index.js, and packages from
+// node_modules directories. process.argv holds the path to gojs, the path
+// to the script and the arguments; process.env, process.exit and
+// process.exitCode work as in Node.js.
+//
+// Once the script has run, run waits for its timers until none are left.
+// An uncaught exception is printed with its location and stack trace and
+// exits with status 1. Ctrl-C stops the script once the running callback
+// returns or calls into Go, and exits with status 130; press it again to
+// kill a script stuck in a loop.
+//
+// Check checks the syntax of the named files, the .js files under the named
+// directories and the files matching the named glob patterns, several at a
+// time. It prints each file with errors as file:line:column: message and
+// exits with status 1 if there were any. With -json, it prints a JSON array
+// of objects with file, line, column and message fields instead, which is
+// empty if all files are valid. The -j flag sets how many files are checked
+// in parallel; it defaults to the number of CPUs.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	goruntime "runtime"
+
+	"github.com/crazy2be/gojs"
+)
+
+func init() {
+	// Keep every call into JavaScriptCore on the main thread.
+	goruntime.LockOSThread()
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: gojs\n")
+	fmt.Fprintf(os.Stderr, "       gojs run file.js [arguments]\n")
+	fmt.Fprintf(os.Stderr, "       gojs check [-json] [-j n] file|dir|pattern...\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	var code int
+	switch args := flag.Args(); {
+	case len(args) == 0:
+		code = replCommand()
+	case args[0] == "run" && len(args) > 1:
+		code = runCommand(args[1], args[2:])
+	case args[0] == "check":
+		code = checkCommand(args[1:])
+	default:
+		usage()
+		code = 2
+	}
+	os.Exit(code)
+}
+
+// newContext creates the context scripts are evaluated in. Native bindings
+// available to every script are installed here.
+func newContext() *gojs.Context {
+	return gojs.NewContext()
+}
+
+func replCommand() int {
+	ctx := newContext()
+	defer ctx.Release()
+
+	if err := runREPL(ctx, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "gojs: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func runCommand(file string, args []string) int {
+	path, err := filepath.Abs(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gojs: %v\n", err)
+		return 1
+	}
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintf(os.Stderr, "gojs: %v\n", err)
+		return 1
+	}
+
+	ctx := newContext()
+	defer ctx.Release()
+
+	r, err := newRunner(ctx, append([]string{os.Args[0], path}, args...), os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gojs: %v\n", err)
+		return 1
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+	go func() {
+		<-interrupts
+		r.interrupt()
+		<-interrupts
+		os.Exit(130)
+	}()
+
+	return r.run(path)
+}
+
+func checkCommand(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print the results as JSON")
+	jobs := flags.Int("j", goruntime.NumCPU(), "number of files to check in parallel")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: gojs check [-json] [-j n] file|dir|pattern...\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 || *jobs < 1 {
+		flags.Usage()
+		return 2
+	}
+
+	files, err := expandPatterns(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gojs: %v\n", err)
+		return 2
+	}
+	failed := checkFiles(files, *jobs)
+	if err := writeCheckResults(os.Stdout, failed, *asJSON); err != nil {
+		fmt.Fprintf(os.Stderr, "gojs: %v\n", err)
+		return 1
+	}
+	if len(failed) > 0 {
+		return 1
+	}
+	return 0
+}
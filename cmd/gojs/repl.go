@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/crazy2be/gojs"
+)
+
+const replHelp = `.exit   Exit the REPL
+.help   Print this help
+
+Press Ctrl-C to abandon the current entry, and Ctrl-D on an empty line to exit.
+An empty line evaluates an incomplete entry as is.
+`
+
+// propertyNamesSource evaluates to a function listing the property names of
+// a value and its prototypes, one per line, for tab completion.
+const propertyNamesSource = `(function (o) {
+	var names = [], seen = Object.create(null);
+	for (; o !== null && o !== undefined; o = Object.getPrototypeOf(o)) {
+		Object.getOwnPropertyNames(o).forEach(function (name) {
+			if (!(name in seen)) {
+				seen[name] = true;
+				names.push(name);
+			}
+		});
+	}
+	return names.join("\n");
+})`
+
+// identPath matches the expressions evaluated for tab completion, so that
+// completing never calls functions.
+var identPath = regexp.MustCompile(`^[\pL_$][\pL\pN_$]*(\.[\pL_$][\pL\pN_$]*)*$`)
+
+// lineReader reads the lines of an entry.
+type lineReader interface {
+	readLine(prompt string) (string, error)
+}
+
+// plainReader reads lines from a pipe or file, without prompts.
+type plainReader struct {
+	in *bufio.Reader
+}
+
+func (r plainReader) readLine(prompt string) (string, error) {
+	line, err := r.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), err
+}
+
+type repl struct {
+	ctx     *gojs.Context
+	out     io.Writer
+	inspect *inspector
+	names   *gojs.Object
+}
+
+func newREPL(ctx *gojs.Context, out io.Writer) (*repl, error) {
+	in, err := newInspector(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := ctx.EvaluateScript(propertyNamesSource, nil, "", 1)
+	if err != nil {
+		return nil, err
+	}
+	// Only Go refers to the function, so it is kept from the garbage
+	// collector.
+	names.Protect()
+	return &repl{ctx: ctx, out: out, inspect: in, names: names.ToObjectOrDie()}, nil
+}
+
+// runREPL runs an interactive session in ctx until the input ends or .exit
+// is entered. Line editing and history are only enabled when in is a
+// terminal.
+func runREPL(ctx *gojs.Context, in *os.File, out io.Writer) error {
+	r, err := newREPL(ctx, out)
+	if err != nil {
+		return err
+	}
+	if err := installConsole(ctx, r.inspect, out, os.Stderr); err != nil {
+		return err
+	}
+
+	fd := int(in.Fd())
+	if !isTerminal(fd) {
+		return r.run(plainReader{bufio.NewReader(in)})
+	}
+	// The terminal is only raw while a line is edited, so that Ctrl-C still
+	// interrupts scripts that do not return.
+	editor := newLineEditor(in, out)
+	editor.raw = func() (func(), error) { return makeRaw(fd) }
+	editor.complete = r.complete
+	if path := historyPath(); path != "" {
+		editor.loadHistory(path)
+	}
+	return r.run(historyReader{editor})
+}
+
+// historyReader adds each line read by a line editor to its history.
+type historyReader struct {
+	*lineEditor
+}
+
+func (r historyReader) readLine(prompt string) (string, error) {
+	line, err := r.lineEditor.readLine(prompt)
+	if err == nil && strings.TrimSpace(line) != "" {
+		r.addHistory(line)
+	}
+	return line, err
+}
+
+// historyPath returns the file history is saved to: $GOJS_HISTORY if it is
+// set, even to the empty string to disable saving, or ~/.gojs_history.
+func historyPath() string {
+	if path, ok := os.LookupEnv("GOJS_HISTORY"); ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".gojs_history")
+}
+
+func (r *repl) run(in lineReader) error {
+	var (
+		entry       []string
+		interrupted bool
+	)
+	for {
+		prompt := "> "
+		if len(entry) > 0 {
+			prompt = "... "
+		}
+		line, err := in.readLine(prompt)
+		if err == errInterrupted {
+			if len(entry) == 0 && interrupted {
+				return nil
+			}
+			if len(entry) == 0 {
+				fmt.Fprintln(r.out, "(To exit, press Ctrl-C again or Ctrl-D or type .exit)")
+			}
+			entry, interrupted = nil, len(entry) == 0
+			continue
+		}
+		interrupted = false
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(entry) == 0 && strings.HasPrefix(strings.TrimSpace(line), ".") {
+			switch cmd := strings.TrimSpace(line); cmd {
+			case ".exit":
+				return nil
+			case ".help":
+				fmt.Fprint(r.out, replHelp)
+			default:
+				fmt.Fprintf(r.out, "Invalid REPL keyword %s\n", cmd)
+			}
+			continue
+		}
+
+		entry = append(entry, line)
+		src := strings.Join(entry, "\n")
+		if strings.TrimSpace(src) == "" {
+			entry = nil
+			continue
+		}
+		if line != "" && r.incomplete(src) {
+			continue
+		}
+		entry = nil
+		r.eval(src)
+	}
+}
+
+// incomplete reports whether src is a syntax error only because it ends too
+// early, so that more lines should be read.
+func (r *repl) incomplete(src string) bool {
+	err := r.ctx.CheckScriptSyntax(src, "repl", 1)
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "Unexpected end of script") ||
+		strings.Contains(msg, "Unexpected EOF")
+}
+
+// eval evaluates an entry and prints its result. Entries that look like an
+// object literal are evaluated as one rather than as a block.
+func (r *repl) eval(src string) {
+	trimmed := strings.TrimSpace(src)
+	if strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}") {
+		if r.ctx.CheckScriptSyntax("("+src+")", "repl", 1) == nil {
+			src = "(" + src + ")"
+		}
+	}
+
+	global := r.ctx.GlobalObject()
+	ret, err := r.ctx.EvaluateScript(src, global, "repl", 1)
+	if err != nil {
+		fmt.Fprintf(r.out, "Uncaught %v\n", err)
+		return
+	}
+	global.SetProperty("_", ret, 0)
+	fmt.Fprintln(r.out, r.inspect.inspect(ret))
+}
+
+// complete completes global names, or property names after a dotted path.
+func (r *repl) complete(line []rune, pos int) (int, []string) {
+	start := pos
+	for start > 0 && isWordRune(line[start-1]) {
+		start--
+	}
+	word := string(line[start:pos])
+
+	base, prefix := "", word
+	if i := strings.LastIndex(word, "."); i >= 0 {
+		base, prefix = word[:i], word[i+1:]
+		if !identPath.MatchString(base) {
+			return 0, nil
+		}
+	}
+
+	var candidates []string
+	for _, name := range r.propertyNames(base) {
+		if !strings.HasPrefix(name, prefix) || !isIdentifier(name) {
+			continue
+		}
+		if base != "" {
+			name = base + "." + name
+		}
+		candidates = append(candidates, name)
+	}
+	sort.Strings(candidates)
+	return pos - start, candidates
+}
+
+// propertyNames lists the properties of the value of expr, or of the global
+// object if expr is empty.
+func (r *repl) propertyNames(expr string) []string {
+	global := r.ctx.GlobalObject()
+	val := global.ToValue()
+	if expr != "" {
+		v, err := r.ctx.EvaluateScript(expr, global, "", 1)
+		if err != nil {
+			return nil
+		}
+		val = v
+	}
+	ret, err := r.names.CallAsFunction(global, []*gojs.Value{val})
+	if err != nil {
+		return nil
+	}
+	return strings.Split(ret.ToStringOrDie(), "\n")
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r == '$' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' ||
+		r >= '0' && r <= '9' || r > 0x7f
+}
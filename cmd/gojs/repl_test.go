@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func runScripted(t *testing.T, input string) string {
+	ctx := newContext()
+	defer ctx.Release()
+
+	var out bytes.Buffer
+	r, err := newREPL(ctx, &out)
+	if err != nil {
+		t.Fatalf("newREPL failed: %v", err)
+	}
+	if err := r.run(plainReader{bufio.NewReader(strings.NewReader(input))}); err != nil {
+		t.Fatalf("r.run failed: %v", err)
+	}
+	return out.String()
+}
+
+func TestREPL(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"1 + 2\n", "3\n"},
+		{"'a' + 'b'\n", "\"ab\"\n"},
+		{"var x = 5\nx * 2\n_ + 1\n", "undefined\n10\n11\n"},
+		{"function f(a) {\nreturn a + 1;\n}\nf(1)\n", "undefined\n2\n"},
+		{"[1, 'two', [3, [4, [5, [6]]]]]\n", "[ 1, \"two\", [ 3, [ 4, [ 5, [Array] ] ] ] ]\n"},
+		{"{a: 1, 'b-c': {d: null}}\n", "{ a: 1, \"b-c\": { d: null } }\n"},
+		{"var o = {}; o.self = o; o\n", "{ self: [Circular] }\n"},
+		{"Math.max\n", "[Function: max]\n"},
+		{"new (function Point() { this.x = 1; })\n", "Point { x: 1 }\n"},
+		{"nope\n", "Uncaught ReferenceError: Can't find variable: nope\n"},
+		{"(1 +\n\n", "Uncaught SyntaxError: Unexpected end of script\n"},
+		{".exit\n1\n", ""},
+		{".bogus\n", "Invalid REPL keyword .bogus\n"},
+	}
+	for _, test := range tests {
+		if got := runScripted(t, test.input); got != test.want {
+			t.Errorf("input %q printed %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestREPLComplete(t *testing.T) {
+	ctx := newContext()
+	defer ctx.Release()
+	r, err := newREPL(ctx, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("newREPL failed: %v", err)
+	}
+	ctx.EvaluateScript("var obj = {alpha: 1, beta: {gamma: 2}}", nil, "", 1)
+
+	tests := []struct {
+		line       string
+		wordLen    int
+		candidates []string
+	}{
+		{"ob", 2, []string{"obj"}},
+		{"x = obj.b", 5, []string{"obj.beta"}},
+		{"obj.beta.ga", 11, []string{"obj.beta.gamma"}},
+		{"Math.P", 6, []string{"Math.PI"}},
+		{"f().a", 0, nil},
+	}
+	for _, test := range tests {
+		line := []rune(test.line)
+		wordLen, candidates := r.complete(line, len(line))
+		if wordLen != test.wordLen || !reflect.DeepEqual(candidates, test.candidates) {
+			t.Errorf("complete(%q) = %d, %q, want %d, %q", test.line, wordLen, candidates, test.wordLen, test.candidates)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resolveModule returns the file that require(request) loads from a module
+// in dir. Relative and absolute requests name a file or directory; other
+// requests are looked up in the node_modules directories of dir and its
+// parents.
+func resolveModule(request, dir string) (string, error) {
+	if isPathRequest(request) {
+		path := request
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if file, ok := loadAsPath(path); ok {
+			return file, nil
+		}
+		return "", fmt.Errorf("Cannot find module '%s'", request)
+	}
+
+	for d := dir; ; d = filepath.Dir(d) {
+		if filepath.Base(d) != "node_modules" {
+			if file, ok := loadAsPath(filepath.Join(d, "node_modules", request)); ok {
+				return file, nil
+			}
+		}
+		if parent := filepath.Dir(d); parent == d {
+			break
+		}
+	}
+	return "", fmt.Errorf("Cannot find module '%s'", request)
+}
+
+func isPathRequest(request string) bool {
+	return request == "." || request == ".." ||
+		strings.HasPrefix(request, "./") || strings.HasPrefix(request, "../") ||
+		filepath.IsAbs(request)
+}
+
+// loadAsPath finds the file path refers to, trying the .js and .json
+// extensions and then path as a directory.
+func loadAsPath(path string) (string, bool) {
+	if file, ok := loadAsFile(path); ok {
+		return file, true
+	}
+	return loadAsDirectory(path)
+}
+
+func loadAsFile(path string) (string, bool) {
+	for _, name := range []string{path, path + ".js", path + ".json"} {
+		if fi, err := os.Stat(name); err == nil && fi.Mode().IsRegular() {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// loadAsDirectory finds the main file of a package directory, as named by
+// its package.json or index.js otherwise.
+func loadAsDirectory(dir string) (string, bool) {
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var pkg struct {
+			Main string `json:"main"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && pkg.Main != "" {
+			main := filepath.Join(dir, pkg.Main)
+			if file, ok := loadAsFile(main); ok {
+				return file, true
+			}
+			if file, ok := loadAsFile(filepath.Join(main, "index")); ok {
+				return file, true
+			}
+		}
+	}
+	return loadAsFile(filepath.Join(dir, "index"))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveModule(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"app/main.js":                              "",
+		"app/lib/util.js":                          "",
+		"app/data.json":                            "{}",
+		"app/dir/index.js":                         "",
+		"app/node_modules/pkg/package.json":        `{"main": "src/entry"}`,
+		"app/node_modules/pkg/src/entry.js":        "",
+		"node_modules/shared/index.js":             "",
+		"app/node_modules/pkg/node_modules/dep.js": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := filepath.Join(root, "app")
+	tests := []struct {
+		request, dir, want string
+	}{
+		{"./lib/util", app, "app/lib/util.js"},
+		{"./lib/util.js", app, "app/lib/util.js"},
+		{"../main", filepath.Join(app, "lib"), "app/main.js"},
+		{"./data", app, "app/data.json"},
+		{"./dir", app, "app/dir/index.js"},
+		{filepath.Join(app, "main"), "/", "app/main.js"},
+		{"pkg", filepath.Join(app, "lib"), "app/node_modules/pkg/src/entry.js"},
+		{"shared", app, "node_modules/shared/index.js"},
+		{"dep", filepath.Join(app, "node_modules/pkg/src"), "app/node_modules/pkg/node_modules/dep.js"},
+		{"./missing", app, ""},
+		{"missing", app, ""},
+	}
+	for _, test := range tests {
+		got, err := resolveModule(test.request, test.dir)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("resolveModule(%q) = %q, want an error", test.request, got)
+			}
+			continue
+		}
+		if want := filepath.Join(root, test.want); err != nil || got != want {
+			t.Errorf("resolveModule(%q, %q) = %q, %v, want %q", test.request, test.dir, got, err, want)
+		}
+	}
+}
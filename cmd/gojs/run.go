@@ -0,0 +1,457 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/crazy2be/gojs"
+)
+
+// runtimeSource evaluates to a function that installs timers, require and
+// process on the global object, given the Go host callbacks. It returns the
+// functions the runner calls: main to run the main module and fire to run
+// a due timer. Every callback is guarded, so that uncaught exceptions reach
+// host.uncaught with their stack.
+const runtimeSource = `(function (global, host) {
+	"use strict";
+	var slice = Array.prototype.slice;
+
+	function hostError(e) {
+		return typeof e === "string" ? new Error(e) : e;
+	}
+
+	function guard(fn, thisArg, args) {
+		try {
+			fn.apply(thisArg, args);
+		} catch (e) {
+			host.uncaught(e);
+		}
+	}
+
+	var timers = Object.create(null);
+
+	function addTimer(repeat, fn, delay, args) {
+		if (typeof fn !== "function") {
+			throw new TypeError("The callback argument must be a function");
+		}
+		var id = host.schedule(Number(delay) || 0, repeat);
+		timers[id] = { fn: fn, args: args, repeat: repeat };
+		return id;
+	}
+
+	function clearTimer(id) {
+		if (id in timers) {
+			delete timers[id];
+			host.cancel(id);
+		}
+	}
+
+	global.setTimeout = function (fn, delay) {
+		return addTimer(false, fn, delay, slice.call(arguments, 2));
+	};
+	global.setInterval = function (fn, delay) {
+		return addTimer(true, fn, delay, slice.call(arguments, 2));
+	};
+	global.setImmediate = function (fn) {
+		return addTimer(false, fn, 0, slice.call(arguments, 1));
+	};
+	global.clearTimeout = global.clearInterval = global.clearImmediate = clearTimer;
+
+	var cache = Object.create(null);
+	var mainModule;
+
+	function dirname(filename) {
+		return host.dirname(filename);
+	}
+
+	function load(filename) {
+		var module = cache[filename];
+		if (module) {
+			return module.exports;
+		}
+		module = cache[filename] = {
+			id: filename,
+			filename: filename,
+			exports: {},
+			loaded: false
+		};
+		if (!mainModule) {
+			mainModule = module;
+		}
+		try {
+			if (/\.json$/.test(filename)) {
+				module.exports = JSON.parse(host.read(filename));
+			} else {
+				var fn = host.compile(filename);
+				fn.call(module.exports, module.exports, makeRequire(dirname(filename)), module, filename, dirname(filename));
+			}
+		} catch (e) {
+			delete cache[filename];
+			throw hostError(e);
+		}
+		module.loaded = true;
+		return module.exports;
+	}
+
+	function makeRequire(dir) {
+		function resolve(request) {
+			if (typeof request !== "string" || request === "") {
+				throw new TypeError("The module name must be a non-empty string");
+			}
+			try {
+				return host.resolve(request, dir);
+			} catch (e) {
+				throw hostError(e);
+			}
+		}
+		function require(request) {
+			return load(resolve(request));
+		}
+		require.resolve = resolve;
+		require.cache = cache;
+		require.main = mainModule;
+		return require;
+	}
+
+	global.require = makeRequire(host.cwd());
+
+	var process = global.process = {
+		argv: host.argv,
+		env: host.env,
+		platform: host.platform,
+		exitCode: undefined,
+		cwd: host.cwd,
+		exit: function (code) {
+			host.exit(code === undefined ? process.exitCode : code);
+		},
+		stdout: { write: function (s) { host.write(1, String(s)); return true; } },
+		stderr: { write: function (s) { host.write(2, String(s)); return true; } }
+	};
+
+	return {
+		main: function (filename) {
+			guard(load, undefined, [filename]);
+		},
+		fire: function (id) {
+			var t = timers[id];
+			if (!t) {
+				return;
+			}
+			if (!t.repeat) {
+				delete timers[id];
+			}
+			guard(t.fn, global, t.args);
+		}
+	};
+})`
+
+// moduleWrapper turns the source of a CommonJS module into a function. The
+// opening line is prepended to the first line of the module so that line
+// numbers stay the same.
+const moduleWrapper = "(function (exports, require, module, __filename, __dirname) { "
+
+// exitSignal is thrown by process.exit to unwind the script.
+const exitSignal = "process.exit"
+
+// runner runs a script file and the callbacks it schedules, as gojs run.
+type runner struct {
+	ctx     *gojs.Context
+	inspect *inspector
+	loop    *eventLoop
+	stdout  io.Writer
+	stderr  io.Writer
+
+	api *gojs.Object // the object returned by runtimeSource
+
+	exiting     bool
+	exitCode    int
+	interrupted atomic.Bool
+}
+
+func newRunner(ctx *gojs.Context, argv []string, stdout, stderr io.Writer) (*runner, error) {
+	in, err := newInspector(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r := &runner{
+		ctx:     ctx,
+		inspect: in,
+		loop:    newEventLoop(),
+		stdout:  stdout,
+		stderr:  stderr,
+	}
+	if err := installConsole(ctx, in, stdout, stderr); err != nil {
+		return nil, err
+	}
+
+	host, err := r.host(argv)
+	if err != nil {
+		return nil, err
+	}
+	factory, err := ctx.EvaluateScript(runtimeSource, nil, "", 1)
+	if err != nil {
+		return nil, err
+	}
+	global := ctx.GlobalObject()
+	api, err := factory.ToObjectOrDie().CallAsFunction(global, []*gojs.Value{global.ToValue(), host.ToValue()})
+	if err != nil {
+		return nil, err
+	}
+	r.api = api.ToObjectOrDie()
+	r.api.ToValue().Protect()
+	return r, nil
+}
+
+// host creates the object of Go callbacks the runtime is built on.
+func (r *runner) host(argv []string) (*gojs.Object, error) {
+	ctx := r.ctx
+	args := make([]*gojs.Value, len(argv))
+	for i, arg := range argv {
+		args[i] = ctx.NewStringValue(arg)
+	}
+	argvArray, err := ctx.NewArray(args)
+	if err != nil {
+		return nil, err
+	}
+	env := make(map[string]*gojs.Value)
+	for _, kv := range os.Environ() {
+		if i := strings.IndexByte(kv, '='); i > 0 {
+			env[kv[:i]] = ctx.NewStringValue(kv[i+1:])
+		}
+	}
+	envObject, err := ctx.NewObjectWithProperties(env)
+	if err != nil {
+		return nil, err
+	}
+
+	fn := func(f gojs.GoFunctionCallback) *gojs.Value {
+		return ctx.NewFunctionWithCallback(f).ToValue()
+	}
+	return ctx.NewObjectWithProperties(map[string]*gojs.Value{
+		"argv":     argvArray.ToValue(),
+		"env":      envObject.ToValue(),
+		"platform": ctx.NewStringValue(goruntime.GOOS),
+		"schedule": fn(r.schedule),
+		"cancel":   fn(r.cancel),
+		"resolve":  fn(r.resolve),
+		"read":     fn(r.read),
+		"compile":  fn(r.compile),
+		"dirname":  fn(r.dirname),
+		"cwd":      fn(r.cwd),
+		"write":    fn(r.write),
+		"exit":     fn(r.exit),
+		"uncaught": fn(r.uncaught),
+	})
+}
+
+// arg returns the ith argument, or undefined if there are fewer.
+func arg(ctx *gojs.Context, args []*gojs.Value, i int) *gojs.Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return ctx.NewUndefinedValue()
+}
+
+// checkInterrupt unwinds the script once Ctrl-C was pressed, whenever it
+// calls back into Go.
+func (r *runner) checkInterrupt() {
+	if r.interrupted.Load() {
+		r.stop(130)
+		panic(exitSignal)
+	}
+}
+
+func (r *runner) stop(code int) {
+	if !r.exiting {
+		r.exiting, r.exitCode = true, code
+	}
+}
+
+func (r *runner) schedule(ctx *gojs.Context, _, _ *gojs.Object, args []*gojs.Value) *gojs.Value {
+	r.checkInterrupt()
+	ms, err := arg(ctx, args, 0).ToNumber()
+	if err != nil {
+		panic(err)
+	}
+	repeat := arg(ctx, args, 1).ToBoolean()
+	id := r.loop.schedule(time.Duration(ms*float64(time.Millisecond)), repeat)
+	return ctx.NewNumberValue(float64(id))
+}
+
+func (r *runner) cancel(ctx *gojs.Context, _, _ *gojs.Object, args []*gojs.Value) *gojs.Value {
+	r.loop.cancel(int(arg(ctx, args, 0).ToNumberOrDie()))
+	return ctx.NewUndefinedValue()
+}
+
+func (r *runner) resolve(ctx *gojs.Context, _, _ *gojs.Object, args []*gojs.Value) *gojs.Value {
+	file, err := resolveModule(arg(ctx, args, 0).ToStringOrDie(), arg(ctx, args, 1).ToStringOrDie())
+	if err != nil {
+		panic(err)
+	}
+	return ctx.NewStringValue(file)
+}
+
+func (r *runner) read(ctx *gojs.Context, _, _ *gojs.Object, args []*gojs.Value) *gojs.Value {
+	data, err := os.ReadFile(arg(ctx, args, 0).ToStringOrDie())
+	if err != nil {
+		panic(err)
+	}
+	return ctx.NewStringValue(string(data))
+}
+
+// compile reads a CommonJS module and returns its module function.
+func (r *runner) compile(ctx *gojs.Context, _, _ *gojs.Object, args []*gojs.Value) *gojs.Value {
+	r.checkInterrupt()
+	filename := arg(ctx, args, 0).ToStringOrDie()
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		panic(err)
+	}
+	src := string(data)
+	if strings.HasPrefix(src, "#!") {
+		src = "//" + src[2:]
+	}
+	fn, err := ctx.EvaluateScript(moduleWrapper+src+"\n})", nil, filename, 1)
+	if err != nil {
+		panic(err)
+	}
+	return fn
+}
+
+func (r *runner) dirname(ctx *gojs.Context, _, _ *gojs.Object, args []*gojs.Value) *gojs.Value {
+	return ctx.NewStringValue(filepath.Dir(arg(ctx, args, 0).ToStringOrDie()))
+}
+
+func (r *runner) cwd(ctx *gojs.Context, _, _ *gojs.Object, args []*gojs.Value) *gojs.Value {
+	dir, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	return ctx.NewStringValue(dir)
+}
+
+func (r *runner) write(ctx *gojs.Context, _, _ *gojs.Object, args []*gojs.Value) *gojs.Value {
+	r.checkInterrupt()
+	w := r.stdout
+	if arg(ctx, args, 0).ToNumberOrDie() == 2 {
+		w = r.stderr
+	}
+	io.WriteString(w, arg(ctx, args, 1).ToStringOrDie())
+	return ctx.NewUndefinedValue()
+}
+
+// exit implements process.exit. It stops the event loop and throws, so that
+// the rest of the current callback does not run unless it catches the
+// exception.
+func (r *runner) exit(ctx *gojs.Context, _, _ *gojs.Object, args []*gojs.Value) *gojs.Value {
+	code := 0
+	if c := arg(ctx, args, 0); !c.IsUndefined() && !c.IsNull() {
+		code = int(c.ToNumberOrDie())
+	}
+	r.stop(code)
+	panic(exitSignal)
+}
+
+// uncaught reports an exception that escaped a callback and stops the
+// script with exit code 1.
+func (r *runner) uncaught(ctx *gojs.Context, _, _ *gojs.Object, args []*gojs.Value) *gojs.Value {
+	if !r.exiting {
+		r.stop(1)
+		fmt.Fprintln(r.stderr, r.describeError(arg(ctx, args, 0)))
+	}
+	return ctx.NewUndefinedValue()
+}
+
+// describeError formats an uncaught exception as its source location,
+// followed by the exception and its stack trace.
+func (r *runner) describeError(e *gojs.Value) string {
+	if !e.IsObject() {
+		return "Uncaught " + r.inspect.inspect(e)
+	}
+	obj := e.ToObjectOrDie()
+	prop := func(name string) string {
+		v, err := obj.GetProperty(name)
+		if err != nil || v.IsUndefined() {
+			return ""
+		}
+		return r.inspect.stringOf(v)
+	}
+
+	var b strings.Builder
+	if file := prop("sourceURL"); file != "" {
+		b.WriteString(file + ":" + prop("line"))
+		if column := prop("column"); column != "" {
+			b.WriteString(":" + column)
+		}
+		b.WriteString(": ")
+	}
+	b.WriteString("Uncaught " + r.inspect.stringOf(e))
+	for _, frame := range strings.Split(prop("stack"), "\n") {
+		if frame == "" {
+			continue
+		}
+		// JavaScriptCore formats frames as function@location.
+		if i := strings.IndexByte(frame, '@'); i > 0 {
+			frame = frame[:i] + " (" + frame[i+1:] + ")"
+		} else if i == 0 {
+			frame = frame[1:]
+		}
+		b.WriteString("\n    at " + frame)
+	}
+	return b.String()
+}
+
+// run runs the main module and then the event loop until no timers are
+// left, the script exits or it is interrupted. It returns the exit code:
+// process.exitCode, or the code passed to process.exit, 1 after an uncaught
+// exception and 130 after an interrupt.
+func (r *runner) run(filename string) int {
+	mainFn, _ := r.api.GetProperty("main")
+	fire, _ := r.api.GetProperty("fire")
+
+	if _, err := mainFn.ToObjectOrDie().CallAsFunction(r.api, []*gojs.Value{r.ctx.NewStringValue(filename)}); err != nil {
+		r.reportError(err)
+	}
+	r.loop.run(func(id int) {
+		if _, err := fire.ToObjectOrDie().CallAsFunction(r.api, []*gojs.Value{r.ctx.NewNumberValue(float64(id))}); err != nil {
+			r.reportError(err)
+		}
+	}, func() bool {
+		if r.interrupted.Load() {
+			r.stop(130)
+		}
+		return r.exiting
+	})
+
+	if !r.exiting {
+		if process, err := r.ctx.GlobalObject().GetProperty("process"); err == nil && process.IsObject() {
+			if code, err := process.ToObjectOrDie().GetProperty("exitCode"); err == nil && code.IsNumber() {
+				return int(code.ToNumberOrDie())
+			}
+		}
+	}
+	return r.exitCode
+}
+
+// reportError reports an exception thrown outside of the guarded callbacks,
+// which only happens if the runtime itself fails.
+func (r *runner) reportError(err error) {
+	if !r.exiting {
+		r.stop(1)
+		fmt.Fprintf(r.stderr, "gojs: %v\n", err)
+	}
+}
+
+// interrupt asks the script to stop at the next opportunity: once the
+// current callback returns, or when it calls back into Go. It may be called
+// from any goroutine.
+func (r *runner) interrupt() {
+	r.interrupted.Store(true)
+	r.loop.wakeUp()
+}
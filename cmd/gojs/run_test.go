@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runFiles writes files to a temporary directory and runs main.js there.
+func runFiles(t *testing.T, files map[string]string, args ...string) (stdout, stderr string, code int) {
+	dir := t.TempDir()
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := newContext()
+	defer ctx.Release()
+
+	var out, errOut bytes.Buffer
+	main := filepath.Join(dir, "main.js")
+	r, err := newRunner(ctx, append([]string{"gojs", main}, args...), &out, &errOut)
+	if err != nil {
+		t.Fatalf("newRunner failed: %v", err)
+	}
+	code = r.run(main)
+	return out.String(), strings.Replace(errOut.String(), dir, "DIR", -1), code
+}
+
+func TestRun(t *testing.T) {
+	stdout, stderr, code := runFiles(t, map[string]string{
+		"main.js": `#!/usr/bin/env gojs
+			var lib = require("./lib");
+			console.log("%s has %d items", "list", 3, {extra: true});
+			console.error("args:", process.argv.slice(2));
+			console.log(lib.name, require("./data.json").answer, require.main === module);
+			setTimeout(function (a) { console.log("timeout", a); }, 10, "arg");
+			setImmediate(function () { console.log("immediate"); });
+			var n = 0, id = setInterval(function () {
+				if (++n === 3) {
+					clearInterval(id);
+					console.log("interval", n);
+				}
+			}, 1);
+			clearTimeout(setTimeout(function () { console.log("cleared"); }, 0));`,
+		"lib/index.js": `exports.name = "lib";`,
+		"data.json":    `{"answer": 42}`,
+	}, "a", "b")
+
+	if want := "list has 3 items { extra: true }\nlib 42 true\nimmediate\ninterval 3\ntimeout arg\n"; stdout != want {
+		t.Errorf("stdout is %q, want %q", stdout, want)
+	}
+	if want := "args: [ \"a\", \"b\" ]\n"; stderr != want {
+		t.Errorf("stderr is %q, want %q", stderr, want)
+	}
+	if code != 0 {
+		t.Errorf("exit code %d, want 0", code)
+	}
+}
+
+func TestRunExit(t *testing.T) {
+	tests := []struct {
+		src  string
+		out  string
+		code int
+	}{
+		{`console.log("a"); process.exit(3); console.log("b");`, "a\n", 3},
+		{`setTimeout(function () { process.exit(4); }, 1); setTimeout(function () { console.log("b"); }, 50);`, "", 4},
+		{`process.exitCode = 5;`, "", 5},
+		{`try { process.exit(6); } catch (e) {} console.log("caught");`, "caught\n", 6},
+	}
+	for _, test := range tests {
+		stdout, stderr, code := runFiles(t, map[string]string{"main.js": test.src})
+		if stdout != test.out || stderr != "" || code != test.code {
+			t.Errorf("%s: printed %q, %q and exited with %d, want %q, \"\" and %d", test.src, stdout, stderr, code, test.out, test.code)
+		}
+	}
+}
+
+func TestRunUncaught(t *testing.T) {
+	_, stderr, code := runFiles(t, map[string]string{
+		"main.js": "function f() {\n  null.x;\n}\nsetTimeout(f, 0);\nsetTimeout(function () { console.log('after'); }, 10);",
+	})
+	if code != 1 {
+		t.Errorf("exit code %d, want 1", code)
+	}
+	lines := strings.Split(stderr, "\n")
+	if !strings.HasPrefix(lines[0], "DIR/main.js:2:") || !strings.Contains(lines[0], "Uncaught TypeError") {
+		t.Errorf("first line of the report is %q, want the location and the exception", lines[0])
+	}
+	if len(lines) < 2 || !strings.HasPrefix(lines[1], "    at f (DIR/main.js:2:") {
+		t.Errorf("report %q does not include the stack trace", stderr)
+	}
+
+	_, stderr, code = runFiles(t, map[string]string{"main.js": `require("./nowhere");`})
+	if code != 1 || !strings.Contains(stderr, "Uncaught Error: Cannot find module './nowhere'") {
+		t.Errorf("missing module reported as %q with exit code %d", stderr, code)
+	}
+	_, stderr, code = runFiles(t, map[string]string{"main.js": "var x = ;"})
+	if code != 1 || !strings.HasPrefix(stderr, "DIR/main.js:1:") || !strings.Contains(stderr, "SyntaxError") {
+		t.Errorf("syntax error reported as %q with exit code %d", stderr, code)
+	}
+}
+
+func TestRunInterrupt(t *testing.T) {
+	ctx := newContext()
+	defer ctx.Release()
+
+	dir := t.TempDir()
+	main := filepath.Join(dir, "main.js")
+	src := `setInterval(function () { console.log("tick"); }, 1);`
+	if err := os.WriteFile(main, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	r, err := newRunner(ctx, []string{"gojs", main}, &out, &out)
+	if err != nil {
+		t.Fatalf("newRunner failed: %v", err)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		r.interrupt()
+	}()
+	if code := r.run(main); code != 130 {
+		t.Errorf("exit code %d after an interrupt, want 130", code)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func ioctlTermios(fd int, req uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), req, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// isTerminal reports whether fd refers to a terminal.
+func isTerminal(fd int) bool {
+	var t syscall.Termios
+	return ioctlTermios(fd, syscall.TCGETS, &t) == nil
+}
+
+// makeRaw puts the terminal fd into raw mode, so that the line editor sees
+// every key press, and returns a function restoring the previous mode.
+// Output processing is left on so that "\n" still starts a new line.
+func makeRaw(fd int) (restore func(), err error) {
+	var old syscall.Termios
+	if err := ioctlTermios(fd, syscall.TCGETS, &old); err != nil {
+		return nil, err
+	}
+
+	raw := old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := ioctlTermios(fd, syscall.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		ioctlTermios(fd, syscall.TCSETS, &old)
+	}, nil
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// Line editing is only implemented for Linux terminals; elsewhere input is
+// read line by line without history recall or completion.
+
+func isTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (restore func(), err error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
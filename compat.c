@@ -0,0 +1,92 @@
+#include "compat.h"
+
+#ifdef GOJS_HAVE_TYPED_ARRAYS
+
+int gojs_is_array(JSContextRef ctx, JSValueRef value)
+{
+	return JSValueIsArray( ctx, value );
+}
+
+int gojs_is_date(JSContextRef ctx, JSValueRef value)
+{
+	return JSValueIsDate( ctx, value );
+}
+
+#else
+
+int gojs_is_array(JSContextRef ctx, JSValueRef value)
+{
+	return -1;
+}
+
+int gojs_is_date(JSContextRef ctx, JSValueRef value)
+{
+	return -1;
+}
+
+#endif
+
+#ifdef GOJS_HAVE_SYMBOLS
+
+int gojs_symbols()
+{
+	return 1;
+}
+
+JSValueRef gojs_make_symbol(JSContextRef ctx, JSStringRef description)
+{
+	return JSValueMakeSymbol( ctx, description );
+}
+
+bool gojs_has_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef* exception)
+{
+	return JSObjectHasPropertyForKey( ctx, object, key, exception );
+}
+
+JSValueRef gojs_get_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef* exception)
+{
+	return JSObjectGetPropertyForKey( ctx, object, key, exception );
+}
+
+void gojs_set_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef value, JSPropertyAttributes attributes, JSValueRef* exception)
+{
+	JSObjectSetPropertyForKey( ctx, object, key, value, attributes, exception );
+}
+
+bool gojs_delete_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef* exception)
+{
+	return JSObjectDeletePropertyForKey( ctx, object, key, exception );
+}
+
+#else
+
+int gojs_symbols()
+{
+	return 0;
+}
+
+JSValueRef gojs_make_symbol(JSContextRef ctx, JSStringRef description)
+{
+	return NULL;
+}
+
+bool gojs_has_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef* exception)
+{
+	return false;
+}
+
+JSValueRef gojs_get_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef* exception)
+{
+	return NULL;
+}
+
+void gojs_set_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef value, JSPropertyAttributes attributes, JSValueRef* exception)
+{
+}
+
+bool gojs_delete_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef* exception)
+{
+	return false;
+}
+
+#endif
@@ -0,0 +1,42 @@
+#ifndef GOJS_COMPAT_H
+#define GOJS_COMPAT_H
+
+#include <JavaScriptCore/JSObjectRef.h>
+#include <JavaScriptCore/JSValueRef.h>
+
+// Typed arrays, JSValueIsArray and JSValueIsDate were added to the C API
+// together, after the 3.0 API, with typed arrays in a header of their own.
+#if defined(__has_include)
+#if __has_include(<JavaScriptCore/JSTypedArray.h>)
+#include <JavaScriptCore/JSTypedArray.h>
+#define GOJS_HAVE_TYPED_ARRAYS 1
+#endif
+#endif
+
+// Symbols and the functions taking property keys as values were added in
+// 2.26. The version is only known from the GLib API, which the 3.0 API
+// does not have.
+#if defined(__has_include)
+#if __has_include(<jsc/jsc.h>)
+#include <jsc/jsc.h>
+#if JSC_CHECK_VERSION(2, 26, 0)
+#define GOJS_HAVE_SYMBOLS 1
+#endif
+#endif
+#endif
+
+// gojs_is_array and gojs_is_date return 1 or 0, or -1 if the C API cannot
+// tell.
+int gojs_is_array(JSContextRef ctx, JSValueRef value);
+int gojs_is_date(JSContextRef ctx, JSValueRef value);
+
+// Without GOJS_HAVE_SYMBOLS, gojs_symbols returns 0 and the functions after
+// it must not be called.
+int gojs_symbols();
+JSValueRef gojs_make_symbol(JSContextRef ctx, JSStringRef description);
+bool gojs_has_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef* exception);
+JSValueRef gojs_get_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef* exception);
+void gojs_set_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef value, JSPropertyAttributes attributes, JSValueRef* exception);
+bool gojs_delete_property_for_key(JSContextRef ctx, JSObjectRef object, JSValueRef key, JSValueRef* exception);
+
+#endif
@@ -3,7 +3,12 @@ package gojs
 // #include <stdlib.h>
 // #include <JavaScriptCore/JSContextRef.h>
 import "C"
-import "unsafe"
+import (
+	"context"
+	"runtime"
+	"sync"
+	"unsafe"
+)
 
 // Context wraps a JavaScriptCore JSContextRef.
 type Context struct {
@@ -13,11 +18,45 @@ type Context struct {
 // GlobalContext wraps a JavaScriptCore JSGlobalContextRef.
 type GlobalContext Context
 
+// contextData holds the Go-side state attached to a global context. It is
+// shared by every *Context that refers to the same global context, including
+// the ones handed to native callbacks.
+type contextData struct {
+	refs            int
+	moduleLoader    ModuleLoader
+	moduleRuntime   *Object
+	helpers         map[string]*Object
+	largeIntPolicy  LargeIntPolicy
+	strictArguments bool
+	goContext       context.Context
+	wrappers        *Object
+
+	// released holds values to unprotect on the thread that owns the
+	// context, queued by finalizers running on their own goroutine.
+	releasedMu sync.Mutex
+	released   []C.JSValueRef
+
+	// received holds the settlements of promises returned by async
+	// iterators over channels, queued by the goroutines receiving their
+	// values for AwaitReceives to run; receiving counts the promises
+	// waiting for a value.
+	receivedMu     sync.Mutex
+	received       []func(ctx *Context)
+	receiving      int
+	receivedSignal chan struct{}
+}
+
+var (
+	contextsMu sync.Mutex
+	contexts   = make(map[C.JSGlobalContextRef]*contextData)
+)
+
 func NewContext() *Context {
 	ctx := new(Context)
 
 	c_nil := C.JSClassRef(unsafe.Pointer(uintptr(0)))
 	ctx.ref = C.JSContextRef(C.JSGlobalContextCreate(c_nil))
+	ctx.addRef(1)
 	return ctx
 }
 
@@ -37,15 +76,189 @@ func NewGlobalContextFrom(raw RawGlobalContext) *GlobalContext {
 	return ctx
 }
 
+// data returns the Go-side state of the global context ctx belongs to,
+// creating it if needed.
+func (ctx *Context) data() *contextData {
+	global := C.JSContextGetGlobalContext(ctx.ref)
+
+	contextsMu.Lock()
+	defer contextsMu.Unlock()
+	d, ok := contexts[global]
+	if !ok {
+		d = new(contextData)
+		contexts[global] = d
+	}
+	return d
+}
+
+// addRef adjusts the number of references held on the Go-side state of ctx,
+// dropping the state once the last one is released.
+func (ctx *Context) addRef(delta int) {
+	global := C.JSContextGetGlobalContext(ctx.ref)
+
+	contextsMu.Lock()
+	defer contextsMu.Unlock()
+	d, ok := contexts[global]
+	if !ok {
+		d = new(contextData)
+		contexts[global] = d
+	}
+	d.refs += delta
+	if d.refs <= 0 {
+		d.unprotect()
+		delete(contexts, global)
+	}
+}
+
+// unprotect unprotects the values d keeps protected for the context, once
+// the last reference to it is released.
+func (d *contextData) unprotect() {
+	if d.moduleRuntime != nil {
+		d.moduleRuntime.ToValue().UnProtect()
+	}
+	if d.wrappers != nil {
+		d.wrappers.ToValue().UnProtect()
+	}
+	for _, fn := range d.helpers {
+		fn.ToValue().UnProtect()
+	}
+	d.helpers = nil
+}
+
+// heldValues keeps JavaScript values protected from garbage collection
+// while it is reachable from Go.
+type heldValues struct {
+	data *contextData
+	refs []C.JSValueRef
+}
+
+// holdValues protects values until the returned heldValues is garbage
+// collected by Go, for Go functions calling them to keep it reachable.
+func (ctx *Context) holdValues(values ...*Value) *heldValues {
+	ctx.unprotectReleased()
+	h := &heldValues{data: ctx.data()}
+	for _, v := range values {
+		v.Protect()
+		h.refs = append(h.refs, v.ref)
+	}
+	runtime.SetFinalizer(h, func(h *heldValues) {
+		h.data.releasedMu.Lock()
+		h.data.released = append(h.data.released, h.refs...)
+		h.data.releasedMu.Unlock()
+	})
+	return h
+}
+
+// unprotectReleased unprotects the values of the heldValues collected
+// since it last ran.
+func (ctx *Context) unprotectReleased() {
+	data := ctx.data()
+	data.releasedMu.Lock()
+	refs := data.released
+	data.released = nil
+	data.releasedMu.Unlock()
+	for _, ref := range refs {
+		C.JSValueUnprotect(ctx.ref, ref)
+	}
+}
+
+// SetGoContext sets the context.Context injected into native functions
+// called from ctx that take one as a leading parameter, so that they can
+// observe cancellation and deadlines.
+func (ctx *Context) SetGoContext(c context.Context) {
+	ctx.data().goContext = c
+}
+
+// GoContext returns the context.Context set by SetGoContext, or
+// context.Background if none is set.
+func (ctx *Context) GoContext() context.Context {
+	if c := ctx.data().goContext; c != nil {
+		return c
+	}
+	return context.Background()
+}
+
 func (ctx *Context) Retain() {
+	ctx.addRef(1)
 	C.JSGlobalContextRetain(ctx.ref)
 }
 
 func (ctx *Context) Release() {
+	ctx.addRef(-1)
 	C.JSGlobalContextRelease(ctx.ref)
 }
 
+// ContextGroup wraps a JavaScriptCore JSContextGroupRef. Contexts created in
+// the same group share a heap, so values may be passed between them, and
+// share JavaScriptCore's cache of compiled code.
+type ContextGroup struct {
+	ref C.JSContextGroupRef
+}
+
+func NewContextGroup() *ContextGroup {
+	group := new(ContextGroup)
+	group.ref = C.JSContextGroupCreate()
+	return group
+}
+
+func (group *ContextGroup) Retain() {
+	C.JSContextGroupRetain(group.ref)
+}
+
+func (group *ContextGroup) Release() {
+	C.JSContextGroupRelease(group.ref)
+}
+
+// NewContext creates a new global context in group.
+func (group *ContextGroup) NewContext() *Context {
+	ctx := new(Context)
+	ctx.ref = C.JSContextRef(C.JSGlobalContextCreateInGroup(group.ref, nil))
+	ctx.addRef(1)
+	return ctx
+}
+
+// Group returns the context group ctx belongs to.
+func (ctx *Context) Group() *ContextGroup {
+	group := new(ContextGroup)
+	group.ref = C.JSContextGetGroup(ctx.ref)
+	return group
+}
+
 func (ctx *Context) GlobalObject() *Object {
 	ret := C.JSContextGetGlobalObject(ctx.ref)
 	return ctx.newObject(ret)
 }
+
+// helper returns the function that source evaluates to, compiling it once per
+// context. Helpers implement the parts of the API that the C API has no
+// functions for.
+func (ctx *Context) helper(source string) (*Object, error) {
+	data := ctx.data()
+	if fn, ok := data.helpers[source]; ok {
+		return fn, nil
+	}
+
+	val, err := ctx.EvaluateScript(source, nil, "", 1)
+	if err != nil {
+		return nil, err
+	}
+	fn, err := val.ToObject()
+	if err != nil {
+		return nil, err
+	}
+	fn.ToValue().Protect()
+	if data.helpers == nil {
+		data.helpers = make(map[string]*Object)
+	}
+	data.helpers[source] = fn
+	return fn, nil
+}
+
+// callHelper calls the helper compiled from source with args.
+func (ctx *Context) callHelper(source string, args ...*Value) (*Value, error) {
+	fn, err := ctx.helper(source)
+	if err != nil {
+		return nil, err
+	}
+	return fn.CallAsFunction(ctx.GlobalObject(), args)
+}
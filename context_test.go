@@ -29,3 +29,46 @@ func TestContextGlobalObject(t *testing.T) {
 		t.Errorf("ctx.GlobalObject() did not return a javascript object")
 	}
 }
+
+func TestContextGroup(t *testing.T) {
+	group := NewContextGroup()
+	defer group.Release()
+
+	ctx1 := group.NewContext()
+	defer ctx1.Release()
+	ctx2 := group.NewContext()
+	defer ctx2.Release()
+
+	if ctx1.Group().ref != group.ref || ctx2.Group().ref != group.ref {
+		t.Errorf("ctx.Group() did not return the group the context was created in")
+	}
+
+	// Values can be shared between contexts in the same group.
+	obj, err := ctx1.EvaluateScript("({answer: 42})", nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	ctx2.GlobalObject().SetProperty("shared", obj, 0)
+	ret, err := ctx2.EvaluateScript("shared.answer", nil, "", 1)
+	if err != nil || ret.ToNumberOrDie() != 42 {
+		t.Errorf("reading a value shared between contexts returned %v (%v)", ret, err)
+	}
+}
+
+func TestContextReleaseUnprotects(t *testing.T) {
+	group := NewContextGroup()
+	defer group.Release()
+
+	ctx := group.NewContext()
+	if _, err := ctx.callHelper(typeofSource, ctx.NewNumberValue(1)); err != nil {
+		t.Fatalf("ctx.callHelper failed: %v", err)
+	}
+	data := ctx.data()
+	if len(data.helpers) == 0 {
+		t.Fatalf("the helper was not cached")
+	}
+	ctx.Release()
+	if data.helpers != nil {
+		t.Errorf("releasing the context kept %d helpers", len(data.helpers))
+	}
+}
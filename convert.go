@@ -0,0 +1,128 @@
+package gojs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+var (
+	valueType  = reflect.TypeOf((*Value)(nil))
+	objectType = reflect.TypeOf((*Object)(nil))
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// newValueFromGoJSON converts value to JavaScript through its JSON
+// representation, as encoding/json produces it.
+func (ctx *Context) newValueFromGoJSON(value reflect.Value) (*Value, error) {
+	data, err := json.Marshal(value.Interface())
+	if err != nil {
+		return nil, err
+	}
+	return ctx.NewValueFromJSON(data)
+}
+
+// convertTo converts v to a Go value of type typ. Values keep their JavaScript
+// type: strings only convert to strings, booleans to bools and numbers to
+// numbers, with the conversions of jsValueToGoType. undefined and null
+// convert to the zero value of any type, and other values to pointers to
+// their conversion. Native objects convert to the Go values they wrap as
+// unwrapNative does, functions to Go functions bound with BindFunc, and
+// other objects through their JSON representation, as GoValue does.
+func (v *Value) convertTo(typ reflect.Type) (reflect.Value, error) {
+	switch typ {
+	case valueType:
+		return reflect.ValueOf(v), nil
+	case objectType:
+		if v.IsUndefined() || v.IsNull() {
+			return reflect.Zero(typ), nil
+		}
+		if !v.IsObject() {
+			return reflect.Value{}, v.conversionError(typ)
+		}
+		return reflect.ValueOf(v.ToObjectOrDie()), nil
+	}
+	if v.IsUndefined() || v.IsNull() {
+		return reflect.Zero(typ), nil
+	}
+	if v.IsObject() {
+		if goObj, ok := v.ToObjectOrDie().GoObject(); ok {
+			return unwrapNative(reflect.ValueOf(goObj), typ)
+		}
+	}
+	if r, ok, err := jsValueToGoType(v, typ); ok {
+		return r, err
+	}
+	if typ.Kind() == reflect.Ptr && typ != bigIntType {
+		// Pointers tell values apart from undefined, converted above.
+		elem, err := v.convertTo(typ.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		p := reflect.New(typ.Elem())
+		p.Elem().Set(elem)
+		return p, nil
+	}
+
+	r := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.Bool:
+		if !v.IsBoolean() {
+			return reflect.Value{}, v.conversionError(typ)
+		}
+		r.SetBool(v.ToBoolean())
+		return r, nil
+	case reflect.String:
+		if !v.IsString() {
+			return reflect.Value{}, v.conversionError(typ)
+		}
+		r.SetString(v.ToStringOrDie())
+		return r, nil
+	case reflect.Float32, reflect.Float64:
+		if !v.IsNumber() {
+			return reflect.Value{}, v.conversionError(typ)
+		}
+		r.SetFloat(v.ToNumberOrDie())
+		return r, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		// Numbers and BigInts were converted by jsValueToGoType.
+		return reflect.Value{}, v.conversionError(typ)
+	case reflect.Interface:
+		if typ.NumMethod() != 0 {
+			return reflect.Value{}, v.conversionError(typ)
+		}
+		goval, err := v.GoValue()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if goval != nil {
+			r.Set(reflect.ValueOf(goval))
+		}
+		return r, nil
+	case reflect.Func:
+		if !v.IsFunction() {
+			return reflect.Value{}, v.conversionError(typ)
+		}
+		return v.ctx.makeFunc(v.ToObjectOrDie(), nil, typ)
+	}
+
+	if !v.IsObject() {
+		return reflect.Value{}, v.conversionError(typ)
+	}
+	jsonData, err := v.ctx.callHelper(goValueJSONSource, v)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if !jsonData.IsString() {
+		return reflect.Value{}, v.conversionError(typ)
+	}
+	if err := json.Unmarshal([]byte(jsonData.ToStringOrDie()), r.Addr().Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("gojs: cannot convert object to %v: %v", typ, err)
+	}
+	return r, nil
+}
+
+func (v *Value) conversionError(typ reflect.Type) error {
+	return fmt.Errorf("gojs: cannot convert %v to %v", v.Kind(), typ)
+}
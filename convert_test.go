@@ -0,0 +1,101 @@
+package gojs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValue_convertTo(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	type point struct{ X, Y int }
+	tests := []struct {
+		script string
+		want   interface{}
+	}{
+		{"'s'", "s"},
+		{"true", true},
+		{"2.5", 2.5},
+		{"7", int16(7)},
+		{"null", ""},
+		{"undefined", 0},
+		{"({ X: 1, Y: 2 })", point{1, 2}},
+		{"[{ X: 3 }]", []point{{3, 0}}},
+		{"new Map([['a', 1]])", map[string]int{"a": 1}},
+		{"({ a: [1] })", map[string]interface{}{"a": []interface{}{1.0}}},
+		{"new Date(0)", time.Unix(0, 0).UTC()},
+		{"'p'", func() *string { s := "p"; return &s }()},
+		{"3", func() *int { n := 3; return &n }()},
+		{"({ X: 4 })", &point{4, 0}},
+		{"undefined", (*string)(nil)},
+	}
+	for _, test := range tests {
+		val, err := ctx.EvaluateScript(test.script, nil, "", 1)
+		if err != nil {
+			t.Errorf("%s: ctx.EvaluateScript failed: %v", test.script, err)
+			continue
+		}
+		typ := reflect.TypeOf(test.want)
+		r, err := val.convertTo(typ)
+		if err != nil {
+			t.Errorf("%s to %v failed: %v", test.script, typ, err)
+			continue
+		}
+		if !reflect.DeepEqual(r.Interface(), test.want) {
+			t.Errorf("%s to %v = %#v, want %#v", test.script, typ, r.Interface(), test.want)
+		}
+	}
+
+	errorTests := []struct {
+		script string
+		typ    reflect.Type
+	}{
+		{"1", reflect.TypeOf("")},
+		{"'true'", reflect.TypeOf(true)},
+		{"'x'", reflect.TypeOf([]int(nil))},
+		{"1.5", reflect.TypeOf(0)},
+		{"({})", reflect.TypeOf(func() {})},
+		{"'x'", reflect.TypeOf((*int)(nil))},
+	}
+	for _, test := range errorTests {
+		val, err := ctx.EvaluateScript(test.script, nil, "", 1)
+		if err != nil {
+			t.Errorf("%s: ctx.EvaluateScript failed: %v", test.script, err)
+			continue
+		}
+		if r, err := val.convertTo(test.typ); err == nil {
+			t.Errorf("%s to %v = %v, want an error", test.script, test.typ, r)
+		}
+	}
+}
+
+func TestReflectPlainData(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	type item struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	values := map[string]interface{}{
+		"s": item{"a", 2},
+		"m": map[string]int{"x": 1},
+		"l": []item{{"b", 3}},
+	}
+	for name, value := range values {
+		v, err := ctx.plainDataToJSValue(reflect.ValueOf(value))
+		if err != nil {
+			t.Fatalf("%s: ctx.plainDataToJSValue failed: %v", name, err)
+		}
+		ctx.GlobalObject().SetProperty(name, v, 0)
+	}
+	checkScript(t, ctx, "s.name + s.count", "a2")
+	checkScript(t, ctx, "m.x", "1")
+	checkScript(t, ctx, "Array.isArray(l) && l[0].name", "b")
+
+	if _, err := ctx.NewValueFromJSON([]byte("{")); err == nil {
+		t.Errorf("ctx.NewValueFromJSON succeeded for invalid JSON")
+	}
+}
@@ -0,0 +1,72 @@
+package gojs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// maxDateMilliseconds is the largest distance from the Unix epoch, in
+// milliseconds, that a Date can represent.
+const maxDateMilliseconds = 8.64e15
+
+// ErrInvalidDate is returned for Dates whose time value is NaN, such as
+// new Date("not a date"), and for times that Dates cannot represent.
+var ErrInvalidDate = errors.New("gojs: invalid date")
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// NewDateFromTime creates a Date for t. Dates have a precision of a
+// millisecond, so t is truncated to the millisecond.
+func (ctx *Context) NewDateFromTime(t time.Time) (*Object, error) {
+	ms := t.UnixMilli()
+	if math.Abs(float64(ms)) > maxDateMilliseconds {
+		return nil, ErrInvalidDate
+	}
+	return ctx.NewDateWithMilliseconds(float64(ms))
+}
+
+// getTimeSource evaluates to Date.prototype.getTime, which unlike valueOf
+// cannot be overridden by the Date.
+const getTimeSource = `(function (date) { return Date.prototype.getTime.call(date); })`
+
+// Time returns the time of the Date v, in UTC. It returns ErrInvalidDate for
+// invalid Dates.
+func (v *Value) Time() (time.Time, error) {
+	if !v.IsDate() {
+		return time.Time{}, fmt.Errorf("gojs: %v is not a Date", v.Kind())
+	}
+	ms, err := v.ctx.callHelper(getTimeSource, v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	n := ms.ToNumberOrDie()
+	if n != n {
+		return time.Time{}, ErrInvalidDate
+	}
+	return time.UnixMilli(int64(n)).UTC(), nil
+}
+
+// newDurationValue converts d to a number of milliseconds, the unit of
+// time of JavaScript.
+func (ctx *Context) newDurationValue(d time.Duration) *Value {
+	return ctx.NewNumberValue(float64(d) / float64(time.Millisecond))
+}
+
+// toDuration converts a number of milliseconds to a time.Duration.
+func (v *Value) toDuration() (time.Duration, error) {
+	ms, err := v.ToNumber()
+	if err != nil {
+		return 0, err
+	}
+	d := ms * float64(time.Millisecond)
+	if d != d || math.Abs(d) >= math.MaxInt64 {
+		return 0, fmt.Errorf("gojs: %v milliseconds is out of the range of time.Duration", ms)
+	}
+	return time.Duration(d), nil
+}
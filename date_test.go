@@ -0,0 +1,81 @@
+package gojs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewDateFromTime(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	tm := time.Date(2013, time.December, 26, 10, 30, 0, 250e6, time.UTC)
+	date, err := ctx.NewDateFromTime(tm)
+	if err != nil {
+		t.Fatalf("ctx.NewDateFromTime failed: %v", err)
+	}
+	ctx.GlobalObject().SetProperty("d", date.ToValue(), 0)
+	checkScript(t, ctx, "d.toISOString()", "2013-12-26T10:30:00.250Z")
+
+	back, err := date.ToValue().Time()
+	if err != nil {
+		t.Fatalf("date.Time failed: %v", err)
+	}
+	if !back.Equal(tm) {
+		t.Errorf("date.Time() = %v, want %v", back, tm)
+	}
+
+	if _, err := ctx.NewDateFromTime(time.Date(300000, 1, 1, 0, 0, 0, 0, time.UTC)); err != ErrInvalidDate {
+		t.Errorf("ctx.NewDateFromTime out of range returned %v, want ErrInvalidDate", err)
+	}
+}
+
+func TestValue_Time(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript("var d = new Date(Date.UTC(2000, 0, 2)); d.valueOf = function () { return 0; }; d", nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	tm, err := val.Time()
+	if want := time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC); err != nil || !tm.Equal(want) {
+		t.Errorf("val.Time() = %v, %v, want %v", tm, err, want)
+	}
+
+	invalid, err := ctx.NewDateWithString("not a date")
+	if err != nil {
+		t.Fatalf("ctx.NewDateWithString failed: %v", err)
+	}
+	if _, err := invalid.ToValue().Time(); err != ErrInvalidDate {
+		t.Errorf("Time() of an invalid date returned %v, want ErrInvalidDate", err)
+	}
+	if _, err := invalid.ToValue().GoValue(); err != ErrInvalidDate {
+		t.Errorf("GoValue() of an invalid date returned %v, want ErrInvalidDate", err)
+	}
+	if _, err := ctx.NewNumberValue(0).Time(); err == nil {
+		t.Errorf("Time() of a number succeeded")
+	}
+}
+
+func TestReflectTimes(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	tm := time.Date(2020, time.February, 29, 12, 0, 0, 0, time.UTC)
+	ctx.GlobalObject().SetProperty("t", ctx.reflectToJSValue(reflect.ValueOf(tm)), 0)
+	ctx.GlobalObject().SetProperty("d", ctx.reflectToJSValue(reflect.ValueOf(1500*time.Microsecond)), 0)
+	checkScript(t, ctx, "t instanceof Date && t.toISOString()", "2020-02-29T12:00:00.000Z")
+	checkScript(t, ctx, "d", "1.5")
+
+	later := ctx.NewFunctionWithNative(func(t time.Time, d time.Duration) time.Time {
+		return t.Add(d)
+	})
+	ctx.GlobalObject().SetProperty("later", later.ToValue(), 0)
+	checkScript(t, ctx, "later(t, 60000).toISOString()", "2020-02-29T12:01:00.000Z")
+
+	if _, err := ctx.EvaluateScript("later('2020-02-29', 1)", nil, "", 1); err == nil {
+		t.Errorf("passing a string for a time.Time succeeded")
+	}
+}
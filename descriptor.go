@@ -0,0 +1,153 @@
+package gojs
+
+// Descriptor describes a property, like the property descriptors of
+// Object.defineProperty. A descriptor with a getter or a setter describes
+// an accessor property, computed each time it is read or written. Other
+// descriptors describe data properties holding Value.
+//
+// Getters and setters may be Go functions, Get and Set, or JavaScript
+// functions, Getter and Setter. Get and Set are called with the object the
+// property was read from or written to; they may panic to throw an
+// exception, like the callbacks of NewFunctionWithCallback.
+type Descriptor struct {
+	Value    *Value
+	Writable bool
+
+	Get    func(ctx *Context, this *Object) *Value
+	Set    func(ctx *Context, this *Object, value *Value)
+	Getter *Object
+	Setter *Object
+
+	Enumerable   bool
+	Configurable bool
+}
+
+// IsAccessor reports whether d describes an accessor property.
+func (d *Descriptor) IsAccessor() bool {
+	return d.Get != nil || d.Set != nil || d.Getter != nil || d.Setter != nil
+}
+
+// definePropertySource evaluates to a function calling Object.defineProperty
+// with a descriptor built from its arguments.
+const definePropertySource = `(function (obj, key, accessor, value, get, set, writable, enumerable, configurable) {
+	var desc = { enumerable: enumerable, configurable: configurable };
+	if (accessor) {
+		if (get !== null) {
+			desc.get = get;
+		}
+		if (set !== null) {
+			desc.set = set;
+		}
+	} else {
+		desc.value = value;
+		desc.writable = writable;
+	}
+	Object.defineProperty(obj, key, desc);
+})`
+
+// DefineProperty defines the property name of obj as described by d, like
+// Object.defineProperty. Attributes that are false in d are false in the
+// property, even if it already existed.
+func (obj *Object) DefineProperty(name string, d Descriptor) error {
+	return obj.DefinePropertyForKey(obj.ctx.NewStringValue(name), d)
+}
+
+// DefinePropertyForKey is like DefineProperty, for a property whose key is
+// key, which may be a symbol.
+func (obj *Object) DefinePropertyForKey(key *Value, d Descriptor) error {
+	ctx := obj.ctx
+	value := d.Value
+	if value == nil {
+		value = ctx.NewUndefinedValue()
+	}
+
+	get, set := ctx.NewNullValue(), ctx.NewNullValue()
+	switch {
+	case d.Get != nil:
+		get = ctx.NewFunctionWithCallback(func(ctx *Context, _, this *Object, _ []*Value) *Value {
+			ret := d.Get(ctx, this)
+			if ret == nil {
+				return ctx.NewUndefinedValue()
+			}
+			return ret
+		}).ToValue()
+	case d.Getter != nil:
+		get = d.Getter.ToValue()
+	}
+	switch {
+	case d.Set != nil:
+		set = ctx.NewFunctionWithCallback(func(ctx *Context, _, this *Object, args []*Value) *Value {
+			value := ctx.NewUndefinedValue()
+			if len(args) > 0 {
+				value = args[0]
+			}
+			d.Set(ctx, this, value)
+			return ctx.NewUndefinedValue()
+		}).ToValue()
+	case d.Setter != nil:
+		set = d.Setter.ToValue()
+	}
+
+	_, err := ctx.callHelper(definePropertySource, obj.ToValue(), key,
+		ctx.NewBooleanValue(d.IsAccessor()), value, get, set,
+		ctx.NewBooleanValue(d.Writable), ctx.NewBooleanValue(d.Enumerable), ctx.NewBooleanValue(d.Configurable))
+	return err
+}
+
+// getOwnPropertyDescriptorSource evaluates to Object.getOwnPropertyDescriptor.
+const getOwnPropertyDescriptorSource = `(function (obj, key) {
+	return Object.getOwnPropertyDescriptor(obj, key);
+})`
+
+// GetOwnPropertyDescriptor returns the descriptor of the property name of
+// obj, not looking at its prototypes, or nil if obj has no such property.
+// The getters and setters of accessor properties are returned as Getter and
+// Setter, whether they were defined from Go or not.
+func (obj *Object) GetOwnPropertyDescriptor(name string) (*Descriptor, error) {
+	return obj.GetOwnPropertyDescriptorForKey(obj.ctx.NewStringValue(name))
+}
+
+// GetOwnPropertyDescriptorForKey is like GetOwnPropertyDescriptor, for a
+// property whose key is key, which may be a symbol.
+func (obj *Object) GetOwnPropertyDescriptorForKey(key *Value) (*Descriptor, error) {
+	ret, err := obj.ctx.callHelper(getOwnPropertyDescriptorSource, obj.ToValue(), key)
+	if err != nil {
+		return nil, err
+	}
+	if !ret.IsObject() {
+		return nil, nil
+	}
+	desc := ret.ToObjectOrDie()
+
+	d := new(Descriptor)
+	fields := []struct {
+		name string
+		set  func(v *Value)
+	}{
+		{"value", func(v *Value) { d.Value = v }},
+		{"writable", func(v *Value) { d.Writable = v.ToBoolean() }},
+		{"get", func(v *Value) { d.Getter = functionOrNil(v) }},
+		{"set", func(v *Value) { d.Setter = functionOrNil(v) }},
+		{"enumerable", func(v *Value) { d.Enumerable = v.ToBoolean() }},
+		{"configurable", func(v *Value) { d.Configurable = v.ToBoolean() }},
+	}
+	for _, field := range fields {
+		v, err := desc.GetProperty(field.name)
+		if err != nil {
+			return nil, err
+		}
+		field.set(v)
+	}
+	if d.IsAccessor() {
+		d.Value = nil
+	}
+	return d, nil
+}
+
+// functionOrNil returns v as an object if it is a function.
+func functionOrNil(v *Value) *Object {
+	if !v.IsFunction() {
+		return nil
+	}
+	return v.ToObjectOrDie()
+}
@@ -0,0 +1,101 @@
+package gojs
+
+import (
+	"testing"
+)
+
+func TestDefinePropertyAccessor(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	version := "1.0"
+	config := ctx.NewEmptyObject()
+	err := config.DefineProperty("version", Descriptor{
+		Get: func(ctx *Context, this *Object) *Value {
+			return ctx.NewStringValue(version)
+		},
+		Set: func(ctx *Context, this *Object, value *Value) {
+			if !value.IsString() {
+				panic("version must be a string")
+			}
+			version = value.String()
+		},
+		Enumerable: true,
+	})
+	if err != nil {
+		t.Fatalf("config.DefineProperty failed: %v", err)
+	}
+	ctx.GlobalObject().SetProperty("config", config.ToValue(), 0)
+
+	checkScript(t, ctx, "config.version", "1.0")
+	version = "1.1"
+	checkScript(t, ctx, "config.version", "1.1")
+	checkScript(t, ctx, "config.version = '2.0'; Object.keys(config).join()", "version")
+	if version != "2.0" {
+		t.Errorf("the setter did not run: version = %q", version)
+	}
+	checkScript(t, ctx, "try { config.version = 3; 'no error' } catch (e) { String(e) }", "version must be a string")
+	checkScript(t, ctx, "delete config.version", "false")
+
+	d, err := config.GetOwnPropertyDescriptor("version")
+	if err != nil {
+		t.Fatalf("config.GetOwnPropertyDescriptor failed: %v", err)
+	}
+	if !d.IsAccessor() || d.Getter == nil || d.Setter == nil || d.Value != nil {
+		t.Errorf("descriptor of version = %+v, want an accessor with a getter and setter", d)
+	}
+	if !d.Enumerable || d.Configurable {
+		t.Errorf("descriptor of version is enumerable %v, configurable %v, want true, false", d.Enumerable, d.Configurable)
+	}
+}
+
+func TestDefinePropertyData(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	obj := ctx.NewEmptyObject()
+	if err := obj.DefineProperty("answer", Descriptor{Value: ctx.NewNumberValue(42)}); err != nil {
+		t.Fatalf("obj.DefineProperty failed: %v", err)
+	}
+	ctx.GlobalObject().SetProperty("obj", obj.ToValue(), 0)
+	checkScript(t, ctx, "obj.answer = 1; obj.answer", "42")
+
+	d, err := obj.GetOwnPropertyDescriptor("answer")
+	if err != nil {
+		t.Fatalf("obj.GetOwnPropertyDescriptor failed: %v", err)
+	}
+	if d.IsAccessor() || d.Value.ToNumberOrDie() != 42 || d.Writable || d.Enumerable || d.Configurable {
+		t.Errorf("descriptor of answer = %+v, want a read-only data property of 42", d)
+	}
+
+	// Redefining a non-configurable property fails.
+	if err := obj.DefineProperty("answer", Descriptor{Value: ctx.NewNumberValue(1)}); err == nil {
+		t.Errorf("redefining answer succeeded")
+	}
+
+	if d, err := obj.GetOwnPropertyDescriptor("missing"); err != nil || d != nil {
+		t.Errorf("obj.GetOwnPropertyDescriptor(missing) = %+v, %v, want nil", d, err)
+	}
+	if d, err := obj.GetOwnPropertyDescriptor("toString"); err != nil || d != nil {
+		t.Errorf("obj.GetOwnPropertyDescriptor(toString) = %+v, %v, want nil for an inherited property", d, err)
+	}
+}
+
+func TestDefinePropertyForKey(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	tag, err := ctx.Symbol(SymbolToStringTag)
+	if err != nil {
+		t.Fatalf("ctx.Symbol failed: %v", err)
+	}
+	obj := ctx.NewEmptyObject()
+	err = obj.DefinePropertyForKey(tag, Descriptor{
+		Get: func(ctx *Context, this *Object) *Value { return ctx.NewStringValue("Request") },
+	})
+	if err != nil {
+		t.Fatalf("obj.DefinePropertyForKey failed: %v", err)
+	}
+	ctx.GlobalObject().SetProperty("obj", obj.ToValue(), 0)
+	checkScript(t, ctx, "String(obj)", "[object Request]")
+}
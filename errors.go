@@ -38,6 +38,15 @@ func (ctx *Context) newErrorValue() *errorValue {
 	return &errorValue{ctx, nil}
 }
 
+// newException returns err as an exception for a native callback to throw:
+// JavaScript exceptions unchanged, and other errors as Error objects.
+func (ctx *Context) newException(err error) *errorValue {
+	if errVal, ok := err.(*errorValue); ok {
+		return errVal
+	}
+	return &errorValue{ctx, ctx.newErrorOrPanic(err.Error())}
+}
+
 // Error returns a string describing the exception. If r.ref is nil, it panics.
 //
 // This is because if r.ref is nil, then errorValue is being used improperly.
@@ -55,3 +64,37 @@ func (r errorValue) Error() string {
 	v := r.ctx.newValue(r.ref)
 	return v.ToStringOrDie()
 }
+
+// SyntaxError is returned by CheckScriptSyntax for scripts that do not parse.
+type SyntaxError struct {
+	Message   string // the exception as a string, like "SyntaxError: Unexpected token ';'"
+	SourceURL string
+	Line      int
+	Column    int // zero if JavaScriptCore does not report it
+}
+
+func (e *SyntaxError) Error() string {
+	return e.Message
+}
+
+// newSyntaxError builds a SyntaxError from the exception thrown by
+// JSCheckScriptSyntax, which carries the position as properties. The
+// exception may be unset, for JavaScriptCores that report the failure alone.
+func (ctx *Context) newSyntaxError(exception *errorValue, sourceURL string) *SyntaxError {
+	if exception.ref == nil {
+		return &SyntaxError{Message: "SyntaxError", SourceURL: sourceURL}
+	}
+	err := &SyntaxError{Message: exception.Error(), SourceURL: sourceURL}
+	v := ctx.newValue(exception.ref)
+	if !v.IsObject() {
+		return err
+	}
+	obj := v.ToObjectOrDie()
+	if line, e := obj.GetProperty("line"); e == nil && line.IsNumber() {
+		err.Line = int(line.ToNumberOrDie())
+	}
+	if column, e := obj.GetProperty("column"); e == nil && column.IsNumber() {
+		err.Column = int(column.ToNumberOrDie())
+	}
+	return err
+}
@@ -9,7 +9,7 @@ const source_url = "./test.js"
 
 func print_properties(ctx *gojs.Context, tab_count int, value *gojs.Object) {
 	names := value.CopyPropertyNames()
-	for lp := uint16(0); lp < names.Count(); lp++ {
+	for lp := 0; lp < names.Count(); lp++ {
 		name := names.NameAtIndex(lp)
 		value, _ := value.GetProperty(name)
 		fmt.Printf("%s = ", name)
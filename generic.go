@@ -0,0 +1,136 @@
+package gojs
+
+import (
+	"reflect"
+)
+
+// As converts v to a Go value of type T, as the results of functions bound
+// with BindFunc are converted: strings only convert to strings, numbers to
+// numbers, and so on, while undefined and null convert to the zero value.
+// Common types such as string, float64, int, bool, *Value and *Object are
+// converted without reflection.
+func As[T any](v *Value) (T, error) {
+	var ret T
+	var err error
+	switch p := any(&ret).(type) {
+	case **Value:
+		*p = v
+		return ret, nil
+	case *string:
+		if v.IsString() {
+			*p, err = v.ToString()
+			return ret, err
+		}
+	case *float64:
+		if v.IsNumber() {
+			*p, err = v.ToNumber()
+			return ret, err
+		}
+	case *bool:
+		if v.IsBoolean() {
+			*p = v.ToBoolean()
+			return ret, nil
+		}
+	case **Object:
+		if v.IsObject() {
+			*p = v.ToObjectOrDie()
+			return ret, nil
+		}
+	}
+
+	// Everything else, including the errors for values of the wrong type.
+	r, err := v.convertTo(reflect.TypeOf(&ret).Elem())
+	if err != nil {
+		return ret, err
+	}
+	if r.IsValid() {
+		reflect.ValueOf(&ret).Elem().Set(r)
+	}
+	return ret, nil
+}
+
+// valueOf converts x to a JavaScript value like ValueOf, without reflection
+// for common types.
+func valueOf[T any](ctx *Context, x T) (*Value, error) {
+	switch x := any(x).(type) {
+	case *Value:
+		if x == nil {
+			return ctx.NewUndefinedValue(), nil
+		}
+		return x, nil
+	case string:
+		return ctx.NewStringValue(x), nil
+	case float64:
+		return ctx.NewNumberValue(x), nil
+	case bool:
+		return ctx.NewBooleanValue(x), nil
+	case int:
+		return ctx.newIntValue(int64(x))
+	}
+	return ctx.ValueOf(x)
+}
+
+// Get returns the property name of obj converted to T with As.
+func Get[T any](obj *Object, name string) (T, error) {
+	v, err := obj.GetProperty(name)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return As[T](v)
+}
+
+// Call calls the function fn with args, converted with ValueOf, and returns
+// its result converted to R with As. It returns a *NotAFunctionError if fn
+// is not a function.
+func Call[R any](fn *Object, args ...interface{}) (R, error) {
+	v, err := fn.Invoke(nil, args...)
+	if err != nil {
+		var zero R
+		return zero, err
+	}
+	return As[R](v)
+}
+
+// Eval evaluates the script src and returns its result converted to T with
+// As.
+func Eval[T any](ctx *Context, src string) (T, error) {
+	v, err := ctx.EvaluateScript(src, nil, "", 1)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return As[T](v)
+}
+
+// FuncOf creates a JavaScript function calling fn. Its first argument is
+// converted to A with As, and the result of fn to JavaScript with ValueOf.
+// Errors returned by fn, and arguments that cannot be converted, are
+// thrown as Error objects.
+func FuncOf[A, R any](ctx *Context, fn func(A) (R, error)) *Object {
+	return ctx.NewFunctionWithCallback(func(ctx *Context, _, _ *Object, args []*Value) *Value {
+		arg := ctx.NewUndefinedValue()
+		if len(args) > 0 {
+			arg = args[0]
+		}
+		a, err := As[A](arg)
+		if err != nil {
+			throwError(ctx, err)
+		}
+		r, err := fn(a)
+		if err != nil {
+			throwError(ctx, err)
+		}
+		ret, err := valueOf(ctx, r)
+		if err != nil {
+			throwError(ctx, err)
+		}
+		return ret
+	})
+}
+
+// throwError panics so that the native callback running throws err as an
+// Error object. JavaScript exceptions are thrown unchanged.
+func throwError(ctx *Context, err error) {
+	panic(ctx.newException(err))
+}
@@ -0,0 +1,87 @@
+package gojs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript("({ name: 'gojs', version: 3, tags: ['a', 'b'], ok: true })", nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	obj := val.ToObjectOrDie()
+
+	if name, err := Get[string](obj, "name"); err != nil || name != "gojs" {
+		t.Errorf("Get[string](name) = %q, %v, want gojs", name, err)
+	}
+	if version, err := Get[int](obj, "version"); err != nil || version != 3 {
+		t.Errorf("Get[int](version) = %d, %v, want 3", version, err)
+	}
+	if tags, err := Get[[]string](obj, "tags"); err != nil || len(tags) != 2 || tags[1] != "b" {
+		t.Errorf("Get[[]string](tags) = %v, %v, want [a b]", tags, err)
+	}
+	if ok, err := Get[bool](obj, "ok"); err != nil || !ok {
+		t.Errorf("Get[bool](ok) = %v, %v, want true", ok, err)
+	}
+	if missing, err := Get[string](obj, "missing"); err != nil || missing != "" {
+		t.Errorf("Get[string](missing) = %q, %v, want the zero value", missing, err)
+	}
+	if _, err := Get[string](obj, "version"); err == nil {
+		t.Errorf("Get[string] of a number succeeded")
+	}
+}
+
+func TestCallAndEval(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	fn, err := Eval[*Object](ctx, "(function (a, b) { return a + b; })")
+	if err != nil {
+		t.Fatalf("Eval[*Object] failed: %v", err)
+	}
+	if sum, err := Call[float64](fn, 1, 2.5); err != nil || sum != 3.5 {
+		t.Errorf("Call[float64] = %v, %v, want 3.5", sum, err)
+	}
+	if s, err := Call[string](fn, "a", "b"); err != nil || s != "ab" {
+		t.Errorf("Call[string] = %q, %v, want ab", s, err)
+	}
+	var notFn *NotAFunctionError
+	if _, err := Call[int](ctx.NewEmptyObject()); !errors.As(err, &notFn) {
+		t.Errorf("Call of a plain object returned %v, want a NotAFunctionError", err)
+	}
+
+	if m, err := Eval[map[string]int](ctx, "({ a: 1, b: 2 })"); err != nil || m["b"] != 2 {
+		t.Errorf("Eval[map[string]int] = %v, %v, want map[a:1 b:2]", m, err)
+	}
+	if _, err := Eval[int](ctx, "throw new Error('boom')"); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Eval of a throwing script returned %v, want the exception", err)
+	}
+}
+
+func TestFuncOf(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	greet := FuncOf(ctx, func(name string) (string, error) {
+		if name == "" {
+			return "", errors.New("no name")
+		}
+		return "hello " + name, nil
+	})
+	ctx.GlobalObject().SetProperty("greet", greet.ToValue(), 0)
+	checkScript(t, ctx, "greet('js')", "hello js")
+	checkScript(t, ctx, "try { greet('') } catch (e) { e instanceof Error && e.message }", "no name")
+	checkScript(t, ctx, "try { greet(1) } catch (e) { e instanceof Error }", "true")
+
+	type point struct{ X, Y int }
+	norm := FuncOf(ctx, func(p point) (int, error) {
+		return p.X*p.X + p.Y*p.Y, nil
+	})
+	ctx.GlobalObject().SetProperty("norm", norm.ToValue(), 0)
+	checkScript(t, ctx, "norm({ X: 3, Y: 4 })", "25")
+}
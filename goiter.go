@@ -0,0 +1,254 @@
+package gojs
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// iteratorSource evaluates to a function creating an iterator object from
+// Go callbacks for its next and return methods. The iterator inherits from
+// %IteratorPrototype%, so it is itself iterable.
+const iteratorSource = `(function (next, ret) {
+	var proto = Object.prototype;
+	if (typeof Symbol === "function" && Symbol.iterator) {
+		proto = Object.getPrototypeOf(Object.getPrototypeOf([][Symbol.iterator]()));
+	}
+	var it = Object.create(proto);
+	it.next = function () { return next(); };
+	it["return"] = function (value) { ret(); return { done: true, value: value }; };
+	return it;
+})`
+
+// chanIteratorSource evaluates to a function creating an async iterator
+// from Go callbacks for its next and return methods. receive is called with
+// the function resolving the promise next returns.
+const chanIteratorSource = `(function (receive, ret) {
+	var it = {
+		next: function () {
+			return new Promise(function (resolve) { receive(resolve); });
+		},
+		"return": function (value) {
+			ret();
+			return Promise.resolve({ done: true, value: value });
+		}
+	};
+	if (typeof Symbol === "function" && Symbol.asyncIterator) {
+		it[Symbol.asyncIterator] = function () { return this; };
+	}
+	return it;
+})`
+
+// newIterator creates a JavaScript iterator object. next returns the next
+// value, or false once there are no more; stop is called when the script
+// stops iterating early.
+func (ctx *Context) newIterator(next func(ctx *Context) (*Value, bool), stop func()) *Value {
+	nextFn := ctx.NewFunctionWithCallback(func(ctx *Context, _, _ *Object, _ []*Value) *Value {
+		value, ok := next(ctx)
+		return ctx.iteratorResult(value, ok)
+	})
+	stopFn := ctx.NewFunctionWithCallback(func(ctx *Context, _, _ *Object, _ []*Value) *Value {
+		stop()
+		return ctx.NewUndefinedValue()
+	})
+
+	it, err := ctx.callHelper(iteratorSource, nextFn.ToValue(), stopFn.ToValue())
+	if err != nil {
+		panic(err)
+	}
+	return it
+}
+
+// iteratorResult returns the object an iterator's next method returns for
+// value, or for the end of the iteration if ok is false.
+func (ctx *Context) iteratorResult(value *Value, ok bool) *Value {
+	if !ok {
+		value = ctx.NewUndefinedValue()
+	}
+	result, err := ctx.NewObjectWithProperties(map[string]*Value{
+		"done":  ctx.NewBooleanValue(!ok),
+		"value": value,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return result.ToValue()
+}
+
+// newChanIterator converts a channel that can be received from into an
+// async iterator over the values received until it is closed.
+//
+// The promise returned by next is settled at once if a value is ready to be
+// received. Otherwise the script carries on, and a goroutine receives the
+// value for AwaitReceives to settle the promise with on the context's
+// thread.
+func (ctx *Context) newChanIterator(ch reflect.Value) *Value {
+	if ch.IsNil() {
+		return ctx.NewNullValue()
+	}
+	data := ctx.data()
+	stop := make(chan struct{})
+	done := false
+	// resolvers holds the resolve functions of the promises waiting for
+	// values, in the order next was called. received follows the receive
+	// of the last of them.
+	var resolvers []*Value
+	var received chan struct{}
+
+	// resolveWith resolves a promise with the received value v, or with
+	// the end of the iteration if ok is false.
+	resolveWith := func(ctx *Context, resolve *Value, v reflect.Value, ok bool) {
+		var value *Value
+		if ok {
+			value = ctx.reflectToJSValue(v)
+		} else {
+			done = true
+		}
+		if _, err := resolve.ToObjectOrDie().CallAsFunction(nil, []*Value{ctx.iteratorResult(value, ok)}); err != nil {
+			panic(err)
+		}
+	}
+	settle := func(ctx *Context, v reflect.Value, ok bool) {
+		if len(resolvers) == 0 {
+			return // settled by return
+		}
+		resolve := resolvers[0]
+		resolvers = resolvers[1:]
+		data.addReceiving(-1)
+		defer resolve.UnProtect()
+		resolveWith(ctx, resolve, v, ok)
+	}
+
+	receive := ctx.NewFunctionWithCallback(func(ctx *Context, _, _ *Object, args []*Value) *Value {
+		resolve := args[0]
+		if done {
+			resolveWith(ctx, resolve, reflect.Value{}, false)
+			return ctx.NewUndefinedValue()
+		}
+		if len(resolvers) == 0 {
+			// TryRecv returns an invalid value if receiving would block.
+			if v, ok := ch.TryRecv(); v.IsValid() {
+				resolveWith(ctx, resolve, v, ok)
+				return ctx.NewUndefinedValue()
+			}
+		}
+
+		resolve.Protect()
+		resolvers = append(resolvers, resolve)
+		data.addReceiving(1)
+		prev, next := received, make(chan struct{})
+		received = next
+		go func() {
+			defer close(next)
+			if prev != nil {
+				select {
+				case <-prev:
+				case <-stop:
+					return
+				}
+			}
+			chosen, v, ok := reflect.Select([]reflect.SelectCase{
+				{Dir: reflect.SelectRecv, Chan: ch},
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(stop)},
+			})
+			if chosen == 0 {
+				data.queueReceived(func(ctx *Context) { settle(ctx, v, ok) })
+			}
+		}()
+		return ctx.NewUndefinedValue()
+	})
+	ret := ctx.NewFunctionWithCallback(func(ctx *Context, _, _ *Object, _ []*Value) *Value {
+		if !done {
+			done = true
+			close(stop)
+		}
+		for len(resolvers) > 0 {
+			settle(ctx, reflect.Value{}, false)
+		}
+		return ctx.NewUndefinedValue()
+	})
+
+	it, err := ctx.callHelper(chanIteratorSource, receive.ToValue(), ret.ToValue())
+	if err != nil {
+		panic(err)
+	}
+	return it
+}
+
+// addReceiving adjusts the number of promises of async iterators over
+// channels waiting for values.
+func (d *contextData) addReceiving(delta int) {
+	d.receivedMu.Lock()
+	d.receiving += delta
+	d.receivedMu.Unlock()
+}
+
+// queueReceived queues settle to run on the context's thread when
+// AwaitReceives runs, and wakes it up.
+func (d *contextData) queueReceived(settle func(ctx *Context)) {
+	d.receivedMu.Lock()
+	d.received = append(d.received, settle)
+	ready := d.receivedReady()
+	d.receivedMu.Unlock()
+	select {
+	case ready <- struct{}{}:
+	default:
+	}
+}
+
+// receivedReady returns the channel queueReceived signals on, creating it
+// if needed. d.receivedMu must be held.
+func (d *contextData) receivedReady() chan struct{} {
+	if d.receivedSignal == nil {
+		d.receivedSignal = make(chan struct{}, 1)
+	}
+	return d.receivedSignal
+}
+
+// AwaitReceives settles the promises of async iterators over channels whose
+// values were received since it last ran, running the scripts awaiting
+// them, and waits for more until no promise is waiting for a value or c is
+// done. It must be called on the thread that runs scripts in ctx, such as
+// from the host's event loop, as values arrive asynchronously.
+func (ctx *Context) AwaitReceives(c context.Context) error {
+	data := ctx.data()
+	for {
+		data.receivedMu.Lock()
+		received, waiting := data.received, data.receiving
+		data.received = nil
+		ready := data.receivedReady()
+		data.receivedMu.Unlock()
+
+		for _, settle := range received {
+			settle(ctx)
+		}
+		if len(received) > 0 {
+			continue
+		}
+		if waiting == 0 {
+			return nil
+		}
+		select {
+		case <-ready:
+		case <-c.Done():
+			return c.Err()
+		}
+	}
+}
+
+// seqType reports whether t is an iter.Seq or iter.Seq2 type, and how many
+// values it yields. Functions of other types with the same signature, such
+// as func(func(int) bool), stay functions; converting them to iter.Seq opts
+// in to iterators.
+func seqType(t reflect.Type) (isSeq bool, values int) {
+	if t.Kind() != reflect.Func || t.PkgPath() != "iter" {
+		return false, 0
+	}
+	switch {
+	case strings.HasPrefix(t.Name(), "Seq["):
+		return true, 1
+	case strings.HasPrefix(t.Name(), "Seq2["):
+		return true, 2
+	}
+	return false, 0
+}
@@ -0,0 +1,12 @@
+//go:build !go1.23
+
+package gojs
+
+import (
+	"reflect"
+)
+
+// newSeqIterator needs iter.Pull, which was added in Go 1.23.
+func (ctx *Context) newSeqIterator(seq reflect.Value, values int) *Value {
+	panic("gojs: converting iterator functions to JavaScript needs Go 1.23 or later")
+}
@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package gojs
+
+import (
+	"iter"
+	"reflect"
+)
+
+// newSeqIterator converts a function with the signature of iter.Seq or
+// iter.Seq2 into an iterator. Pairs from an iter.Seq2 become [key, value]
+// arrays, like the entries of a Map. The sequence runs lazily, as the
+// script asks for values, on a goroutine that stops once the script
+// finishes iterating or stops early, which for...of does by calling the
+// iterator's return method. Scripts calling next by hand must iterate to
+// the end or call return for the sequence to stop.
+func (ctx *Context) newSeqIterator(seq reflect.Value, values int) *Value {
+	if seq.IsNil() {
+		return ctx.NewNullValue()
+	}
+	yieldType := seq.Type().In(0)
+
+	pull := iter.Seq[[]reflect.Value](func(yield func([]reflect.Value) bool) {
+		fn := reflect.MakeFunc(yieldType, func(args []reflect.Value) []reflect.Value {
+			return []reflect.Value{reflect.ValueOf(yield(args))}
+		})
+		seq.Call([]reflect.Value{fn})
+	})
+	next, stop := iter.Pull(pull)
+	return ctx.newIterator(func(ctx *Context) (*Value, bool) {
+		args, ok := next()
+		if !ok {
+			return nil, false
+		}
+		if values == 1 {
+			return ctx.reflectToJSValue(args[0]), true
+		}
+		pair, err := ctx.NewArray([]*Value{ctx.reflectToJSValue(args[0]), ctx.reflectToJSValue(args[1])})
+		if err != nil {
+			panic(err)
+		}
+		return pair.ToValue(), true
+	}, stop)
+}
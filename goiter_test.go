@@ -0,0 +1,141 @@
+//go:build go1.23
+
+package gojs
+
+import (
+	"context"
+	"iter"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSeqToIterator(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	stopped := false
+	rows := func() iter.Seq[int] {
+		return func(yield func(int) bool) {
+			defer func() { stopped = true }()
+			for i := 1; i <= 5; i++ {
+				if !yield(i * 10) {
+					return
+				}
+			}
+		}
+	}
+	pairs := func() iter.Seq2[string, int] {
+		return func(yield func(string, int) bool) {
+			_ = yield("a", 1) && yield("b", 2)
+		}
+	}
+	global := ctx.GlobalObject()
+	global.SetProperty("rows", ctx.NewFunctionWithNative(rows).ToValue(), 0)
+	global.SetProperty("pairs", ctx.NewFunctionWithNative(pairs).ToValue(), 0)
+
+	tests := []struct {
+		script, want string
+		stops        bool // whether the script finishes or stops rows()
+	}{
+		{`var out = []; for (var x of rows()) out.push(x); out.join()`, "10,20,30,40,50", true},
+		{`var out = []; for (var x of rows()) { out.push(x); if (x == 20) break; } out.join()`, "10,20", true},
+		{`var out = []; for (var p of pairs()) out.push(p[0] + "=" + p[1]); out.join()`, "a=1,b=2", false},
+		{`var it = rows(); it.next().value + "," + it[Symbol.iterator]().next().value`, "10,20", false},
+		{`var it = rows(); it.next(); it["return"](); JSON.stringify(it.next())`, `{"done":true}`, true},
+	}
+	for _, test := range tests {
+		stopped = false
+		ret, err := ctx.EvaluateScript(test.script, nil, "", 1)
+		if err != nil {
+			t.Errorf("%s failed: %v", test.script, err)
+			continue
+		}
+		if got := ret.String(); got != test.want {
+			t.Errorf("%s = %s, want %s", test.script, got, test.want)
+		}
+		if test.stops && !stopped {
+			t.Errorf("%s did not stop the sequence", test.script)
+		}
+	}
+}
+
+func TestChanToAsyncIterator(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	events := func() <-chan string {
+		ch := make(chan string, 3)
+		ch <- "start"
+		ch <- "tick"
+		ch <- "stop"
+		close(ch)
+		return ch
+	}
+	ctx.GlobalObject().SetProperty("events", ctx.NewFunctionWithNative(events).ToValue(), 0)
+
+	_, err := ctx.EvaluateScript(`var got = [];
+		(async function () {
+			for await (const ev of events()) {
+				got.push(ev);
+			}
+		})();`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	got, err := ctx.EvaluateScript("got.join()", nil, "", 1)
+	if err != nil || got.String() != "start,tick,stop" {
+		t.Errorf("for await over a channel received %v (%v), want start,tick,stop", got, err)
+	}
+}
+
+func TestSeqType(t *testing.T) {
+	tests := []struct {
+		fn     interface{}
+		values int
+	}{
+		{iter.Seq[int](nil), 1},
+		{iter.Seq2[string, []int](nil), 2},
+		{func(yield func(int) bool) {}, 0},
+		{func() {}, 0},
+	}
+	for _, test := range tests {
+		isSeq, values := seqType(reflect.TypeOf(test.fn))
+		if isSeq != (test.values > 0) || values != test.values {
+			t.Errorf("seqType(%T) = %v, %d, want %d values", test.fn, isSeq, values, test.values)
+		}
+	}
+}
+
+func TestChanAsyncIteratorDoesNotBlock(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	ch := make(chan int)
+	ctx.GlobalObject().SetProperty("numbers", ctx.NewFunctionWithNative(func() <-chan int { return ch }).ToValue(), 0)
+
+	_, err := ctx.EvaluateScript(`var sum = 0, finished = false;
+		(async function () {
+			for await (const n of numbers()) {
+				sum += n;
+			}
+			finished = true;
+		})();`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+
+	go func() {
+		for i := 1; i <= 3; i++ {
+			ch <- i
+			time.Sleep(time.Millisecond)
+		}
+		close(ch)
+	}()
+	c, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := ctx.AwaitReceives(c); err != nil {
+		t.Fatalf("ctx.AwaitReceives failed: %v", err)
+	}
+	checkScript(t, ctx, "finished + ',' + sum", "true,6")
+}
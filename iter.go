@@ -0,0 +1,124 @@
+//go:build go1.23
+
+package gojs
+
+import (
+	"iter"
+)
+
+// valuesBatch is the number of array elements Values reads from
+// JavaScriptCore at a time.
+const valuesBatch = 256
+
+// Property is a property name and its value, as yielded by EntriesErr.
+type Property struct {
+	Name  string
+	Value *Value
+}
+
+// Keys returns an iterator over the names of the enumerable properties of
+// obj, including inherited ones, in the order a for...in loop visits them.
+// The names are copied when iteration starts.
+func (obj *Object) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		names := obj.CopyPropertyNames()
+		defer names.Release()
+		for i := 0; i < names.Count(); i++ {
+			if !yield(names.NameAtIndex(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Entries returns an iterator over the enumerable properties of obj and
+// their values, like Keys. Properties whose getter throws an exception are
+// skipped; use EntriesErr to see them.
+func (obj *Object) Entries() iter.Seq2[string, *Value] {
+	return func(yield func(string, *Value) bool) {
+		for p, err := range obj.EntriesErr() {
+			if err != nil {
+				continue
+			}
+			if !yield(p.Name, p.Value) {
+				return
+			}
+		}
+	}
+}
+
+// EntriesErr is like Entries, but yields the exception thrown by a getter
+// along with the name of the property, whose Value is then nil. Iteration
+// goes on with the next property unless the loop stops.
+func (obj *Object) EntriesErr() iter.Seq2[Property, error] {
+	return func(yield func(Property, error) bool) {
+		for name := range obj.Keys() {
+			val, err := obj.GetProperty(name)
+			if !yield(Property{name, val}, err) {
+				return
+			}
+		}
+	}
+}
+
+// Element is an array index and its element, as yielded by ValuesErr.
+type Element struct {
+	Index int
+	Value *Value
+}
+
+// Values returns an iterator over the indices and elements of the array
+// obj, from index 0 up to its length when iteration starts. Elements are
+// read from JavaScriptCore in batches. Elements whose getter throws an
+// exception are skipped, and iteration ends early if the length cannot be
+// read or is beyond that of the largest array; use ValuesErr to see why.
+func (obj *Object) Values() iter.Seq2[int, *Value] {
+	return func(yield func(int, *Value) bool) {
+		for e, err := range obj.ValuesErr() {
+			if err != nil {
+				continue
+			}
+			if !yield(e.Index, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesErr is like Values, but yields the exception thrown by a getter
+// along with the index of the element, whose Value is then nil, and goes on
+// with the next element unless the loop stops. If the length cannot be
+// read, it yields the error with an Index of -1 and stops; lengths beyond
+// that of the largest array yield ErrIndexOutOfRange in the same way.
+func (obj *Object) ValuesErr() iter.Seq2[Element, error] {
+	return func(yield func(Element, error) bool) {
+		n, err := obj.Len()
+		if err == nil && uint64(n) > maxArrayIndex+1 {
+			err = ErrIndexOutOfRange
+		}
+		if err != nil {
+			yield(Element{Index: -1}, err)
+			return
+		}
+		batch := make([]*Value, valuesBatch)
+		for start := 0; start < n; {
+			size := min(n-start, len(batch))
+			read, err := obj.readElements(start, batch[:size])
+			for i := 0; i < read; i++ {
+				if !yield(Element{start + i, batch[i]}, nil) {
+					unprotectValues(batch[i:read])
+					return
+				}
+				batch[i].UnProtect()
+			}
+			start += read
+			if err != nil {
+				// Go on after the element that threw.
+				if !yield(Element{Index: start}, err) {
+					return
+				}
+				start++
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+//go:build go1.23
+
+package gojs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestObjectKeysEntries(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript(`var o = Object.create({inherited: 0});
+		o.a = 1; o.b = "two";
+		Object.defineProperty(o, "hidden", { value: 3, enumerable: false });
+		Object.defineProperty(o, "bad", { get: function () { throw new Error("boom"); }, enumerable: true });
+		o.c = null;
+		o`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	obj := val.ToObjectOrDie()
+
+	var keys []string
+	for name := range obj.Keys() {
+		keys = append(keys, name)
+	}
+	if want := []string{"a", "b", "bad", "c", "inherited"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("obj.Keys() yielded %q, want %q", keys, want)
+	}
+
+	entries := make(map[string]string)
+	for name, v := range obj.Entries() {
+		entries[name] = v.String()
+	}
+	if want := map[string]string{"a": "1", "b": "two", "c": "null", "inherited": "0"}; !reflect.DeepEqual(entries, want) {
+		t.Errorf("obj.Entries() yielded %v, want %v", entries, want)
+	}
+
+	var failed []string
+	for p, err := range obj.EntriesErr() {
+		if err != nil {
+			failed = append(failed, p.Name+": "+err.Error())
+		}
+	}
+	if want := []string{"bad: Error: boom"}; !reflect.DeepEqual(failed, want) {
+		t.Errorf("obj.EntriesErr() yielded errors %q, want %q", failed, want)
+	}
+
+	// Stopping early must not yield any more properties.
+	n := 0
+	for range obj.Keys() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("breaking out of obj.Keys() after one key yielded %d", n)
+	}
+}
+
+func TestArrayValues(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript(`var a = []; for (var i = 0; i < 1000; i++) a.push(i * 2);
+		Object.defineProperty(a, 300, { get: function () { throw new Error("boom"); } });
+		a`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+
+	count := 0
+	for i, v := range val.ToObjectOrDie().Values() {
+		if i == 300 {
+			t.Errorf("Values yielded the element whose getter throws")
+		}
+		if v.ToNumberOrDie() != float64(i*2) {
+			t.Errorf("element %d is %v, want %d", i, v, i*2)
+		}
+		count++
+		if i == 800 {
+			break
+		}
+	}
+	if count != 800 {
+		t.Errorf("Values yielded %d elements up to index 800, want 800", count)
+	}
+}
+
+func TestArrayValuesErr(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript(`var a = [1, 2, 3];
+		Object.defineProperty(a, 1, { get: function () { throw new Error("boom"); } });
+		a`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	var got []string
+	for e, err := range val.ToObjectOrDie().ValuesErr() {
+		if err != nil {
+			got = append(got, fmt.Sprintf("%d:%v", e.Index, err))
+			continue
+		}
+		got = append(got, fmt.Sprintf("%d=%v", e.Index, e.Value))
+	}
+	if s := strings.Join(got, " "); s != "0=1 1:Error: boom 2=3" {
+		t.Errorf("ValuesErr yielded %s, want 0=1 1:Error: boom 2=3", s)
+	}
+
+	scripts := map[string]string{
+		"({ length: Infinity })":                               ErrIndexOutOfRange.Error(),
+		"({ get length() { throw new Error('no length'); } })": "Error: no length",
+	}
+	for script, want := range scripts {
+		val, err := ctx.EvaluateScript(script, nil, "", 1)
+		if err != nil {
+			t.Fatalf("%s: ctx.EvaluateScript failed: %v", script, err)
+		}
+		n := 0
+		for e, err := range val.ToObjectOrDie().ValuesErr() {
+			n++
+			if err == nil || err.Error() != want || e.Index != -1 {
+				t.Errorf("%s: ValuesErr yielded %v, %v, want %s at index -1", script, e, err, want)
+			}
+		}
+		if n != 1 {
+			t.Errorf("%s: ValuesErr yielded %d times, want once", script, n)
+		}
+		for range val.ToObjectOrDie().Values() {
+			t.Errorf("%s: Values yielded an element", script)
+		}
+	}
+}
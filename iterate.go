@@ -0,0 +1,125 @@
+package gojs
+
+import (
+	"fmt"
+)
+
+// getIteratorSource evaluates to a function returning the iterator of an
+// iterable, which throws a TypeError for values that are not iterable.
+const getIteratorSource = `(function (iterable) {
+	if (typeof Symbol !== "function" || !Symbol.iterator) {
+		throw new TypeError("iteration is not supported by this JavaScriptCore build");
+	}
+	if (iterable === null || iterable === undefined || typeof iterable[Symbol.iterator] !== "function") {
+		throw new TypeError(String(iterable) + " is not iterable");
+	}
+	return iterable[Symbol.iterator]();
+})`
+
+// Iterate calls fn for each value produced by the iterable v, following the
+// JavaScript iteration protocol as for...of does. Arrays, strings, Maps
+// (whose values are [key, value] arrays), Sets, generators and objects with
+// a Symbol.iterator method are all iterable.
+//
+// If fn returns false, iteration stops and the iterator's return method, if
+// any, is called so that generators can clean up. Iterate returns the first
+// exception thrown while iterating.
+func (v *Value) Iterate(fn func(*Value) bool) (err error) {
+	ctx := v.ctx
+	it, err := ctx.callHelper(getIteratorSource, v)
+	if err != nil {
+		return err
+	}
+	iterator, err := it.ToObject()
+	if err != nil {
+		return err
+	}
+	// The iterator, its next method and the values it produces are only
+	// held from Go while scripts run, so they are protected from garbage
+	// collection.
+	it.Protect()
+	defer it.UnProtect()
+	next, err := iterator.GetProperty("next")
+	if err != nil {
+		return err
+	}
+	if !next.IsObject() || !next.ToObjectOrDie().IsFunction() {
+		return fmt.Errorf("gojs: iterator next method is not a function")
+	}
+	next.Protect()
+	defer next.UnProtect()
+
+	for {
+		value, done, err := iteratorStep(next.ToObjectOrDie(), iterator)
+		if err != nil || done {
+			return err
+		}
+		if !callIterateFunc(fn, value, iterator) {
+			return closeIterator(iterator)
+		}
+	}
+}
+
+// iteratorStep calls the next method of iterator and returns the value it
+// produces, protected, or true once it is done.
+func iteratorStep(next, iterator *Object) (value *Value, done bool, err error) {
+	res, err := next.CallAsFunction(iterator, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if !res.IsObject() {
+		return nil, false, fmt.Errorf("gojs: iterator result %v is not an object", res)
+	}
+	res.Protect()
+	defer res.UnProtect()
+	result := res.ToObjectOrDie()
+	isDone, err := result.GetProperty("done")
+	if err != nil {
+		return nil, false, err
+	}
+	if isDone.ToBoolean() {
+		return nil, true, nil
+	}
+	value, err = result.GetProperty("value")
+	if err != nil {
+		return nil, false, err
+	}
+	value.Protect()
+	return value, false, nil
+}
+
+// callIterateFunc calls fn, closing iterator if fn panics, and unprotects
+// value once fn returns.
+func callIterateFunc(fn func(*Value) bool, value *Value, iterator *Object) (more bool) {
+	defer value.UnProtect()
+	defer func() {
+		if r := recover(); r != nil {
+			closeIterator(iterator)
+			panic(r)
+		}
+	}()
+	return fn(value)
+}
+
+// closeIterator calls the return method of an iterator that was not
+// exhausted.
+func closeIterator(iterator *Object) error {
+	ret, err := iterator.GetProperty("return")
+	if err != nil {
+		return err
+	}
+	if ret.IsUndefined() || ret.IsNull() {
+		return nil
+	}
+	if !ret.IsObject() || !ret.ToObjectOrDie().IsFunction() {
+		return fmt.Errorf("gojs: iterator return method is not a function")
+	}
+	res, err := ret.ToObjectOrDie().CallAsFunction(iterator, nil)
+	if err != nil {
+		return err
+	}
+	if !res.IsObject() {
+		return fmt.Errorf("gojs: iterator result %v is not an object", res)
+	}
+	return nil
+}
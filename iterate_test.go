@@ -0,0 +1,114 @@
+package gojs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValueIterate(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{`[1, 2, 3]`, []string{"1", "2", "3"}},
+		{`"ab"`, []string{"a", "b"}},
+		{`new Set([1, 1, 2])`, []string{"1", "2"}},
+		{`new Map([["a", 1], ["b", 2]])`, []string{"a,1", "b,2"}},
+		{`(function* () { yield "x"; yield "y"; })()`, []string{"x", "y"}},
+		{`({ [Symbol.iterator]: function () {
+			var i = 0;
+			return { next: function () { return { done: i >= 2, value: i++ }; } };
+		} })`, []string{"0", "1"}},
+	}
+	for _, test := range tests {
+		val, err := ctx.EvaluateScript(test.script, nil, "", 1)
+		if err != nil {
+			t.Fatalf("ctx.EvaluateScript(%q) failed: %v", test.script, err)
+		}
+		var got []string
+		if err := val.Iterate(func(v *Value) bool {
+			got = append(got, v.String())
+			return true
+		}); err != nil {
+			t.Errorf("Iterate over %s failed: %v", test.script, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Iterate over %s yielded %q, want %q", test.script, got, test.want)
+		}
+	}
+}
+
+func TestValueIterateGarbageCollect(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	// Only Go holds the generator, which allocates as it runs.
+	val, err := ctx.EvaluateScript(`(function* () {
+		for (var i = 0; i < 5; i++) {
+			var garbage = [];
+			for (var j = 0; j < 1000; j++) garbage.push({ j: j });
+			yield { i: i };
+		}
+	})()`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	var got []string
+	err = val.Iterate(func(v *Value) bool {
+		ctx.GarbageCollect()
+		i, _ := v.ToObjectOrDie().GetProperty("i")
+		got = append(got, i.String())
+		return true
+	})
+	if err != nil || !reflect.DeepEqual(got, []string{"0", "1", "2", "3", "4"}) {
+		t.Errorf("Iterate with garbage collections yielded %q, %v", got, err)
+	}
+}
+
+func TestValueIterateEarlyExit(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	gen, err := ctx.EvaluateScript(`var closed = false;
+		(function* () { try { yield 1; yield 2; yield 3; } finally { closed = true; } })()`, nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	n := 0
+	if err := gen.Iterate(func(v *Value) bool {
+		n++
+		return n < 2
+	}); err != nil {
+		t.Errorf("Iterate failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Iterate called fn %d times, want 2", n)
+	}
+	if closed, _ := ctx.EvaluateScript("closed", nil, "", 1); !closed.ToBoolean() {
+		t.Errorf("stopping iteration did not call the generator's return method")
+	}
+}
+
+func TestValueIterateErrors(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	tests := map[string]string{
+		`42`: "TypeError: 42 is not iterable",
+		`(function* () { yield 1; throw new Error("boom"); })()`:                               "Error: boom",
+		`({ [Symbol.iterator]: function () { return { next: function () { return 1; } }; } })`: "gojs: iterator result 1 is not an object",
+	}
+	for script, want := range tests {
+		val, err := ctx.EvaluateScript(script, nil, "", 1)
+		if err != nil {
+			t.Fatalf("ctx.EvaluateScript(%q) failed: %v", script, err)
+		}
+		err = val.Iterate(func(*Value) bool { return true })
+		if err == nil || err.Error() != want {
+			t.Errorf("Iterate over %s returned %v, want %s", script, err, want)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !jsc40 && !jsc41
+
+package gojs
+
+// By default gojs builds against the JavaScriptCore library of WebKitGTK
+// with the 3.0 API. Build with the jsc40 or jsc41 tag to use the newer 4.0
+// or 4.1 APIs instead. Features missing from the library that is linked,
+// such as typed arrays in the 3.0 API, return errors when used.
+
+// #cgo pkg-config: javascriptcoregtk-3.0
+import "C"
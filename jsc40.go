@@ -0,0 +1,6 @@
+//go:build jsc40 && !jsc41
+
+package gojs
+
+// #cgo pkg-config: javascriptcoregtk-4.0
+import "C"
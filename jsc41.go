@@ -0,0 +1,6 @@
+//go:build jsc41
+
+package gojs
+
+// #cgo pkg-config: javascriptcoregtk-4.1
+import "C"
@@ -0,0 +1,185 @@
+package gojs
+
+// #include <JavaScriptCore/JSObjectRef.h>
+// #include <JavaScriptCore/JSValueRef.h>
+// #include "compat.h"
+import "C"
+import (
+	"fmt"
+)
+
+// Kind is the type of a JavaScript value, as told by the typeof operator,
+// except that null has a kind of its own and functions are objects.
+type Kind uint8
+
+const (
+	KindUndefined Kind = TypeUndefined
+	KindNull      Kind = TypeNull
+	KindBoolean   Kind = TypeBoolean
+	KindNumber    Kind = TypeNumber
+	KindString    Kind = TypeString
+	KindObject    Kind = TypeObject
+	KindSymbol    Kind = TypeSymbol
+	KindBigInt    Kind = TypeBigInt
+)
+
+var kindNames = [...]string{
+	"undefined",
+	"null",
+	"boolean",
+	"number",
+	"string",
+	"object",
+	"symbol",
+	"bigint",
+}
+
+func (k Kind) String() string {
+	if int(k) >= len(kindNames) {
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+	return kindNames[k]
+}
+
+// typeofSource evaluates to the typeof operator.
+const typeofSource = `(function (value) { return typeof value; })`
+
+// Kind returns the kind of v.
+func (v *Value) Kind() Kind {
+	k := Kind(C.JSValueGetType(v.ctx.ref, v.ref))
+	if k != KindObject || v.IsObject() {
+		return k
+	}
+	// JavaScriptCores that predate the symbol and BigInt types in the C API
+	// report symbols and BigInts as objects.
+	t, err := v.ctx.callHelper(typeofSource, v)
+	if err != nil {
+		return k
+	}
+	switch t.ToStringOrDie() {
+	case "symbol":
+		return KindSymbol
+	case "bigint":
+		return KindBigInt
+	}
+	return k
+}
+
+func (v *Value) IsSymbol() bool {
+	return v.Kind() == KindSymbol
+}
+
+func (v *Value) IsBigInt() bool {
+	return v.Kind() == KindBigInt
+}
+
+// IsFunction reports whether v is an object that can be called.
+func (v *Value) IsFunction() bool {
+	return v.IsObject() && v.ToObjectOrDie().IsFunction()
+}
+
+// IsArray reports whether v is an array, as Array.isArray does.
+func (v *Value) IsArray() bool {
+	if is := C.gojs_is_array(v.ctx.ref, v.ref); is >= 0 {
+		return is != 0
+	}
+	return v.IsObject() && v.hasClass("Array")
+}
+
+// IsDate reports whether v is a Date.
+func (v *Value) IsDate() bool {
+	if is := C.gojs_is_date(v.ctx.ref, v.ref); is >= 0 {
+		return is != 0
+	}
+	return v.IsObject() && v.hasClass("Date")
+}
+
+// IsError reports whether v is an Error, including subclasses such as
+// TypeError.
+func (v *Value) IsError() bool {
+	return v.IsObject() && v.hasClass("Error")
+}
+
+// IsPromise reports whether v is a Promise.
+func (v *Value) IsPromise() bool {
+	return v.IsObject() && v.hasClass("Promise")
+}
+
+// IsMap reports whether v is a Map.
+func (v *Value) IsMap() bool {
+	return v.IsObject() && v.hasClass("Map")
+}
+
+// IsSet reports whether v is a Set.
+func (v *Value) IsSet() bool {
+	return v.IsObject() && v.hasClass("Set")
+}
+
+// IsTypedArray reports whether v is a typed array, such as a Uint8Array.
+func (v *Value) IsTypedArray() bool {
+	t := v.TypedArrayType()
+	return t != NotTypedArray && t != ArrayBuffer
+}
+
+// IsArrayBuffer reports whether v is an ArrayBuffer.
+func (v *Value) IsArrayBuffer() bool {
+	return v.TypedArrayType() == ArrayBuffer
+}
+
+// IsInstanceOf reports whether v is an instance of constructor, as the
+// instanceof operator does.
+func (v *Value) IsInstanceOf(constructor *Object) (bool, error) {
+	errVal := v.ctx.newErrorValue()
+	ret := C.JSValueIsInstanceOfConstructor(v.ctx.ref, v.ref, constructor.ref, &errVal.ref)
+	if errVal.ref != nil {
+		return false, errVal
+	}
+	return bool(ret), nil
+}
+
+// hasClassSource evaluates to a function reporting whether an object was
+// created by the built-in constructor named class, whatever its prototype.
+// Classes are told by brand checks, built-in methods that only work on
+// their instances and throw for other objects, rather than by
+// Object.prototype.toString, which Symbol.toStringTag fakes. Promises are
+// told by Promise.resolve, which returns promises of the constructor it is
+// called on unchanged. Errors have no brand check before Error.isError, so
+// without it objects with a Symbol.toStringTag are not taken for errors.
+const hasClassSource = `(function (value, cls) {
+	try {
+		switch (cls) {
+		case "Array":
+			return Array.isArray(value);
+		case "Date":
+			Date.prototype.getTime.call(value);
+			return true;
+		case "Map":
+		case "Set":
+			Object.getOwnPropertyDescriptor(this[cls].prototype, "size").get.call(value);
+			return true;
+		case "Promise":
+			var C = value.constructor;
+			if (C !== this.Promise && !(typeof C === "function" && C.prototype instanceof this.Promise)) {
+				return false;
+			}
+			return this.Promise.resolve.call(C, value) === value;
+		case "Error":
+			if (typeof Error.isError === "function") {
+				return Error.isError(value);
+			}
+			if (typeof Symbol === "function" && Symbol.toStringTag && typeof value[Symbol.toStringTag] === "string") {
+				return false;
+			}
+			return Object.prototype.toString.call(value) === "[object Error]";
+		}
+	} catch (e) {
+	}
+	return false;
+})`
+
+// hasClass reports whether the object v was created by the built-in
+// constructor named class.
+func (v *Value) hasClass(class string) bool {
+	ret, err := v.ctx.callHelper(hasClassSource, v, v.ctx.NewStringValue(class))
+	return err == nil && ret.ToBoolean()
+}
@@ -0,0 +1,121 @@
+package gojs
+
+import (
+	"testing"
+)
+
+func TestValue_Kind(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	tests := []struct {
+		script string
+		kind   Kind
+	}{
+		{"undefined", KindUndefined},
+		{"null", KindNull},
+		{"true", KindBoolean},
+		{"1.5", KindNumber},
+		{"'a'", KindString},
+		{"({})", KindObject},
+		{"(function () {})", KindObject},
+		{"Symbol('s')", KindSymbol},
+		{"BigInt(1)", KindBigInt},
+	}
+	for _, test := range tests {
+		val, err := ctx.EvaluateScript(test.script, nil, "", 1)
+		if err != nil {
+			t.Errorf("%s: ctx.EvaluateScript failed: %v", test.script, err)
+			continue
+		}
+		if k := val.Kind(); k != test.kind {
+			t.Errorf("%s: Kind() = %v, want %v", test.script, k, test.kind)
+		}
+		if typ := val.Type(); typ != uint8(test.kind) {
+			t.Errorf("%s: Type() = %d, want %d", test.script, typ, test.kind)
+		}
+	}
+
+	if s := KindBigInt.String(); s != "bigint" {
+		t.Errorf("KindBigInt.String() = %q, want %q", s, "bigint")
+	}
+	if s := Kind(42).String(); s != "Kind(42)" {
+		t.Errorf("Kind(42).String() = %q, want %q", s, "Kind(42)")
+	}
+}
+
+func TestValue_IsPredicates(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	predicates := map[string]func(*Value) bool{
+		"IsArray":      (*Value).IsArray,
+		"IsDate":       (*Value).IsDate,
+		"IsFunction":   (*Value).IsFunction,
+		"IsPromise":    (*Value).IsPromise,
+		"IsError":      (*Value).IsError,
+		"IsSymbol":     (*Value).IsSymbol,
+		"IsBigInt":     (*Value).IsBigInt,
+		"IsTypedArray": (*Value).IsTypedArray,
+		"IsMap":        (*Value).IsMap,
+		"IsSet":        (*Value).IsSet,
+	}
+	tests := []struct {
+		script string
+		is     string
+	}{
+		{"[1, 2]", "IsArray"},
+		{"new Date(0)", "IsDate"},
+		{"(function () {})", "IsFunction"},
+		{"Promise.resolve(1)", "IsPromise"},
+		{"new TypeError('x')", "IsError"},
+		{"Symbol.iterator", "IsSymbol"},
+		{"BigInt(2)", "IsBigInt"},
+		{"new Float64Array(2)", "IsTypedArray"},
+		{"new Map()", "IsMap"},
+		{"new Set()", "IsSet"},
+		{"({ size: 0 })", ""},
+		{"'[object Array]'", ""},
+		{"new ArrayBuffer(8)", ""},
+		{"Promise.reject(1).catch(function () {})", "IsPromise"},
+		{"new (class extends Error {})()", "IsError"},
+		{"({ [Symbol.toStringTag]: 'Array' })", ""},
+		{"({ [Symbol.toStringTag]: 'Date' })", ""},
+		{"({ [Symbol.toStringTag]: 'Promise', constructor: Promise })", ""},
+		{"({ [Symbol.toStringTag]: 'Error' })", ""},
+		{"({ [Symbol.toStringTag]: 'Map' })", ""},
+	}
+	for _, test := range tests {
+		val, err := ctx.EvaluateScript(test.script, nil, "", 1)
+		if err != nil {
+			t.Errorf("%s: ctx.EvaluateScript failed: %v", test.script, err)
+			continue
+		}
+		for name, is := range predicates {
+			if got, want := is(val), name == test.is; got != want {
+				t.Errorf("%s: %s() = %v, want %v", test.script, name, got, want)
+			}
+		}
+	}
+}
+
+func TestValue_IsInstanceOf(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript("function A() {}; function B() {}; new A()", nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	a, _ := ctx.GlobalObject().GetProperty("A")
+	b, _ := ctx.GlobalObject().GetProperty("B")
+	if is, err := val.IsInstanceOf(a.ToObjectOrDie()); err != nil || !is {
+		t.Errorf("val.IsInstanceOf(A) = %v, %v, want true", is, err)
+	}
+	if is, err := val.IsInstanceOf(b.ToObjectOrDie()); err != nil || is {
+		t.Errorf("val.IsInstanceOf(B) = %v, %v, want false", is, err)
+	}
+	if _, err := val.IsInstanceOf(ctx.NewEmptyObject()); err == nil {
+		t.Errorf("val.IsInstanceOf with a non-constructor succeeded")
+	}
+}
@@ -0,0 +1,233 @@
+package gojs
+
+import (
+	"errors"
+)
+
+// ModuleLoader resolves and fetches the ES modules imported by scripts
+// running in a context.
+type ModuleLoader interface {
+	// Resolve returns the canonical name of the module imported as
+	// specifier by the module named referrer. referrer is empty for modules
+	// imported with ImportModule. Modules resolving to the same name are
+	// only evaluated once per context.
+	Resolve(specifier, referrer string) (string, error)
+
+	// Fetch returns the source of the module with the given canonical name.
+	Fetch(name string) (string, error)
+}
+
+var (
+	// ErrNoModuleLoader is returned by ImportModule when no loader was set
+	// with SetModuleLoader.
+	ErrNoModuleLoader = errors.New("gojs: no module loader set on context")
+
+	// ErrModulesUnsupported is returned by ImportModule when the
+	// JavaScriptCore build lacks the features modules are built on.
+	ErrModulesUnsupported = errors.New("gojs: ES modules are not supported by this JavaScriptCore build (Promise is missing)")
+)
+
+// moduleRuntimeSource evaluates to a function that takes the Go host
+// callbacks and returns the module runtime for a context. The runtime keeps
+// the module map and hands each module body (see modulesrc.go) the object it
+// uses to declare exports and load its dependencies, along with the object
+// holding its imports.
+const moduleRuntimeSource = `(function (host) {
+	"use strict";
+	var modules = Object.create(null);
+
+	function hostError(e) {
+		return typeof e === "string" ? new Error(e) : e;
+	}
+
+	function define(ns, key, get) {
+		Object.defineProperty(ns, key, { get: get, enumerable: true });
+	}
+
+	function load(specifier, referrer) {
+		var name;
+		try {
+			name = host.resolve(specifier, referrer);
+		} catch (e) {
+			throw hostError(e);
+		}
+
+		var mod = modules[name];
+		if (mod) {
+			if (mod.failed) {
+				throw mod.error;
+			}
+			// A module that is still evaluating is part of an import
+			// cycle; its namespace already has all local exports.
+			return mod.namespace;
+		}
+
+		var ns = Object.create(null);
+		if (typeof Symbol === "function" && Symbol.toStringTag) {
+			Object.defineProperty(ns, Symbol.toStringTag, { value: "Module" });
+		}
+		mod = modules[name] = { namespace: ns, failed: false, error: undefined };
+		var imports = Object.create(null);
+
+		try {
+			var body;
+			try {
+				body = host.fetch(name);
+			} catch (e) {
+				throw hostError(e);
+			}
+			body.call(undefined, {
+				meta: { url: name },
+				exports: function (getters) {
+					Object.keys(getters).forEach(function (key) {
+						define(ns, key, getters[key]);
+					});
+				},
+				require: function (specifier) {
+					return load(specifier, name);
+				},
+				binding: function (local, source, key, specifier) {
+					if (key === undefined) {
+						define(imports, local, function () { return source; });
+						return;
+					}
+					if (!(key in source)) {
+						throw new SyntaxError("module '" + specifier + "' does not provide an export named '" + key + "'");
+					}
+					define(imports, local, function () { return source[key]; });
+				},
+				reexport: function (source) {
+					Object.keys(source).forEach(function (key) {
+						if (key !== "default" && !(key in ns)) {
+							define(ns, key, function () { return source[key]; });
+						}
+					});
+				},
+				"import": function (specifier) {
+					return dynamicImport(specifier, name);
+				}
+			}, imports);
+		} catch (e) {
+			mod.failed = true;
+			mod.error = e;
+			throw e;
+		}
+		Object.preventExtensions(ns);
+		return ns;
+	}
+
+	function dynamicImport(specifier, referrer) {
+		return new Promise(function (resolve) {
+			resolve(load(String(specifier), referrer));
+		});
+	}
+
+	return { load: load, "import": dynamicImport };
+})`
+
+// SetModuleLoader sets the loader used to resolve and fetch the modules
+// imported in ctx. Modules that were already evaluated stay cached.
+func (ctx *Context) SetModuleLoader(loader ModuleLoader) {
+	ctx.data().moduleLoader = loader
+}
+
+// ImportModule loads the ES module specifier along with its static imports,
+// evaluates it and returns its namespace object. Modules may use import and
+// export declarations, dynamic import() and import.meta.url.
+//
+// JavaScriptCore does not expose its own module loader, so module sources
+// are rewritten into plain functions before evaluation; see modulesrc.go for
+// the differences from native modules.
+func (ctx *Context) ImportModule(specifier string) (*Object, error) {
+	runtime, err := ctx.moduleRuntime()
+	if err != nil {
+		return nil, err
+	}
+
+	load, err := runtime.GetProperty("load")
+	if err != nil {
+		return nil, err
+	}
+	ret, err := load.ToObjectOrDie().CallAsFunction(runtime, []*Value{
+		ctx.NewStringValue(specifier),
+		ctx.NewStringValue(""),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret.ToObject()
+}
+
+// moduleRuntime returns the module runtime of ctx, creating it on first use.
+func (ctx *Context) moduleRuntime() (*Object, error) {
+	data := ctx.data()
+	if data.moduleLoader == nil {
+		return nil, ErrNoModuleLoader
+	}
+	if data.moduleRuntime != nil {
+		return data.moduleRuntime, nil
+	}
+
+	promise, err := ctx.GlobalObject().GetProperty("Promise")
+	if err != nil {
+		return nil, err
+	}
+	if !promise.IsObject() || !promise.ToObjectOrDie().IsFunction() {
+		return nil, ErrModulesUnsupported
+	}
+
+	factory, err := ctx.EvaluateScript(moduleRuntimeSource, nil, "", 1)
+	if err != nil {
+		return nil, err
+	}
+	host, err := ctx.NewObjectWithProperties(map[string]*Value{
+		"resolve": ctx.NewFunctionWithCallback(moduleResolve).ToValue(),
+		"fetch":   ctx.NewFunctionWithCallback(moduleFetch).ToValue(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret, err := factory.ToObjectOrDie().CallAsFunction(nil, []*Value{host.ToValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	runtime := ret.ToObjectOrDie()
+	runtime.ToValue().Protect()
+	data.moduleRuntime = runtime
+	return runtime, nil
+}
+
+func moduleResolve(ctx *Context, _, _ *Object, args []*Value) *Value {
+	specifier := args[0].ToStringOrDie()
+	referrer := args[1].ToStringOrDie()
+	name, err := ctx.data().moduleLoader.Resolve(specifier, referrer)
+	if err != nil {
+		panic("cannot resolve module '" + specifier + "': " + err.Error())
+	}
+	return ctx.NewStringValue(name)
+}
+
+// moduleFetch fetches a module and compiles it into a function taking the
+// runtime object for the module and the object holding its imports. The
+// module body runs in strict mode within a with statement on the imports,
+// so that imported names resolve to their accessors.
+func moduleFetch(ctx *Context, _, _ *Object, args []*Value) *Value {
+	name := args[0].ToStringOrDie()
+	src, err := ctx.data().moduleLoader.Fetch(name)
+	if err != nil {
+		panic("cannot fetch module '" + name + "': " + err.Error())
+	}
+	body, err := rewriteModule(src)
+	if err != nil {
+		panic(name + ":" + err.Error())
+	}
+
+	// The wrapper stays on the first line so line numbers are unchanged.
+	fn, err := ctx.EvaluateScript("(function (__gojs_module, __gojs_imports) { with (__gojs_imports) "+
+		"(function () { \"use strict\"; "+body+"\n})(); })", nil, name, 1)
+	if err != nil {
+		panic(err)
+	}
+	return fn
+}
@@ -0,0 +1,170 @@
+package gojs
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"testing"
+)
+
+// mapModuleLoader serves modules from a map keyed by absolute path.
+type mapModuleLoader map[string]string
+
+func (m mapModuleLoader) Resolve(specifier, referrer string) (string, error) {
+	if strings.HasPrefix(specifier, "/") {
+		return specifier, nil
+	}
+	return path.Join("/", path.Dir(referrer), specifier), nil
+}
+
+func (m mapModuleLoader) Fetch(name string) (string, error) {
+	src, ok := m[name]
+	if !ok {
+		return "", errors.New("no such module")
+	}
+	return src, nil
+}
+
+func TestImportModule(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	ctx.SetModuleLoader(mapModuleLoader{
+		"/main.js": `
+			import add, {two as TWO} from "./lib/math.js";
+			import * as math from "./lib/math.js";
+			export const four = add(TWO, math.two);
+			export {add};
+			export * from "./lib/math.js";`,
+		"/lib/math.js": `
+			export let two = 2;
+			export default function add(a, b) { return a + b; }`,
+	})
+
+	ns, err := ctx.ImportModule("/main.js")
+	if err != nil {
+		t.Fatalf("ctx.ImportModule failed: %v", err)
+	}
+	for name, want := range map[string]string{"four": "4", "two": "2"} {
+		v, err := ns.GetProperty(name)
+		if err != nil {
+			t.Fatalf("ns.GetProperty(%q) failed: %v", name, err)
+		}
+		if got := v.ToStringOrDie(); got != want {
+			t.Errorf("ns.%s = %s, want %s", name, got, want)
+		}
+	}
+	if v, _ := ns.GetProperty("add"); v == nil || !v.IsObject() || !v.ToObjectOrDie().IsFunction() {
+		t.Errorf("ns.add is not a function")
+	}
+
+	// Modules are only evaluated once.
+	ns2, err := ctx.ImportModule("/main.js")
+	if err != nil {
+		t.Fatalf("ctx.ImportModule failed: %v", err)
+	}
+	if !ns.ToValue().Equals(ns2.ToValue()) {
+		t.Errorf("importing a module twice returned different namespaces")
+	}
+}
+
+func TestImportModuleBindings(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	ctx.SetModuleLoader(mapModuleLoader{
+		"/counter.js": `
+			import {isEven} from "./even.js";
+			export let count = 0;
+			export function increment() { count++; }
+			export const even = function () { return isEven(count); };`,
+		"/even.js": `
+			import {count} from "./counter.js";
+			export function countNow() { return count; }
+			export function isEven(n) { return n % 2 === 0; }`,
+		"/main.js": `
+			import {count, increment, even} from "./counter.js";
+			import * as evenNS from "./even.js";
+			increment();
+			export const live = count;
+			export const parity = even();
+			export const cyclic = evenNS.countNow();
+			let threw = false;
+			try { count = 10; } catch (e) { threw = e instanceof TypeError; }
+			export const readOnly = threw && count === 1;`,
+	})
+
+	ns, err := ctx.ImportModule("/main.js")
+	if err != nil {
+		t.Fatalf("ctx.ImportModule failed: %v", err)
+	}
+	for name, want := range map[string]string{"live": "1", "parity": "false", "cyclic": "1", "readOnly": "true"} {
+		v, err := ns.GetProperty(name)
+		if err != nil {
+			t.Fatalf("ns.GetProperty(%q) failed: %v", name, err)
+		}
+		if got := v.ToStringOrDie(); got != want {
+			t.Errorf("ns.%s = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestImportModuleDynamic(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	ctx.SetModuleLoader(mapModuleLoader{
+		"/main.js": `export const lib = import("./lib.js"); export const url = import.meta.url;`,
+		"/lib.js":  `export const answer = 42;`,
+	})
+
+	ns, err := ctx.ImportModule("/main.js")
+	if err != nil {
+		t.Fatalf("ctx.ImportModule failed: %v", err)
+	}
+	url, _ := ns.GetProperty("url")
+	if url.ToStringOrDie() != "/main.js" {
+		t.Errorf("import.meta.url = %s, want /main.js", url)
+	}
+	lib, _ := ns.GetProperty("lib")
+	ctx.GlobalObject().SetProperty("lib", lib, 0)
+	if _, err := ctx.EvaluateScript("var answer; lib.then(function (ns) { answer = ns.answer; })", nil, "", 1); err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+
+	// The promise settles once the script returns to the event loop, which
+	// JavaScriptCore drains before EvaluateScript returns.
+	answer, err := ctx.EvaluateScript("answer", nil, "", 1)
+	if err != nil || answer.ToStringOrDie() != "42" {
+		t.Errorf("dynamic import resolved to %v (%v), want 42", answer, err)
+	}
+}
+
+func TestImportModuleErrors(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	if _, err := ctx.ImportModule("/main.js"); err != ErrNoModuleLoader {
+		t.Errorf("ctx.ImportModule without a loader returned %v, want ErrNoModuleLoader", err)
+	}
+
+	ctx.SetModuleLoader(mapModuleLoader{
+		"/missing.js": `import "./nowhere.js";`,
+		"/binding.js": `import {nope} from "./lib.js";`,
+		"/syntax.js":  `import {a from "./lib.js"}`,
+		"/lib.js":     `export const yes = 1;`,
+	})
+	tests := map[string]string{
+		"/missing.js": "Error: cannot fetch module '/nowhere.js': no such module",
+		"/binding.js": "SyntaxError: module './lib.js' does not provide an export named 'nope'",
+		"/syntax.js":  "Error: /syntax.js:1:11: SyntaxError: expected '}' but found 'from'",
+	}
+	for name, want := range tests {
+		_, err := ctx.ImportModule(name)
+		if err == nil {
+			t.Errorf("ctx.ImportModule(%q) did not fail", name)
+		} else if err.Error() != want {
+			t.Errorf("ctx.ImportModule(%q) error %q, want %q", name, err, want)
+		}
+	}
+}
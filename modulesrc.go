@@ -0,0 +1,1042 @@
+package gojs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// JavaScriptCore only exposes classic scripts through its C API, so ES
+// modules are rewritten into the body of a function before they are
+// evaluated. Import and export declarations are removed and replaced by
+// calls on the module runtime object (see module.go), which is passed to the
+// function as __gojs_module:
+//
+//	import x, {y as z} from "./a";  =>  var __gojs_m0 = __gojs_module.require("./a");
+//	                                    __gojs_module.binding("x", __gojs_m0, "default", "./a"); ...
+//	import * as ns from "./a";      =>  __gojs_module.binding("ns", __gojs_m0);
+//	export const a = 1;             =>  const a = 1;  (plus a getter for "a")
+//	export default f();             =>  var __gojs_default = f();
+//	import("./b")                   =>  __gojs_module.import("./b")
+//
+// Everything the rewrite adds goes on the first line and removed declarations
+// keep their line breaks, so line numbers in errors and stack traces still
+// match the original source.
+//
+// Imported names are accessors on an object the function body is evaluated
+// with (see moduleFetch), so they read the exporting module's variables on
+// each access: they are live, assigning to them throws a TypeError, and
+// modules in an import cycle may import each other's let and const exports
+// as long as they only use them once initialized. Unlike native modules, a
+// module may declare a variable with the name of one it imports, which
+// shadows the import.
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokPunct
+	tokString
+	tokNumber
+	tokTemplate
+	tokRegExp
+)
+
+type token struct {
+	kind       tokenKind
+	text       string
+	start, end int
+	// nl is set when a line terminator precedes the token.
+	nl bool
+}
+
+// moduleSyntaxError describes a module declaration that could not be parsed.
+type moduleSyntaxError struct {
+	line, column int
+	msg          string
+}
+
+func (e *moduleSyntaxError) Error() string {
+	return fmt.Sprintf("%d:%d: SyntaxError: %s", e.line, e.column, e.msg)
+}
+
+//=========================================================
+// Lexer
+//
+
+type moduleLexer struct {
+	src    string
+	pos    int
+	toks   []token
+	braces []byte // 'b' for a block brace, 't' for a template substitution
+	// parens records for each open parenthesis whether it starts the
+	// condition of an if, while, for or with statement.
+	parens []bool
+	// afterCondition is set when the last token closed such a condition,
+	// after which a slash starts a regular expression.
+	afterCondition bool
+	nl             bool
+}
+
+// conditionKeyword lists the keywords whose parenthesized condition is
+// followed by a statement rather than an operator.
+var conditionKeyword = map[string]bool{
+	"if": true, "while": true, "for": true, "with": true,
+}
+
+// regexpAfterKeyword lists the keywords after which a slash starts a regular
+// expression literal rather than a division.
+var regexpAfterKeyword = map[string]bool{
+	"return": true, "typeof": true, "instanceof": true, "in": true,
+	"of": true, "new": true, "delete": true, "void": true, "throw": true,
+	"case": true, "do": true, "else": true, "yield": true, "await": true,
+	"default": true, "extends": true,
+}
+
+func lexModule(src string) ([]token, error) {
+	l := &moduleLexer{src: src}
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind == tokEOF {
+			return l.toks, nil
+		}
+		l.toks = append(l.toks, tok)
+	}
+}
+
+func (l *moduleLexer) errorf(pos int, format string, args ...interface{}) error {
+	line := 1 + strings.Count(l.src[:pos], "\n")
+	column := pos - strings.LastIndex(l.src[:pos], "\n")
+	return &moduleSyntaxError{line, column, fmt.Sprintf(format, args...)}
+}
+
+func (l *moduleLexer) regexpAllowed() bool {
+	if len(l.toks) == 0 {
+		return true
+	}
+	prev := l.toks[len(l.toks)-1]
+	switch prev.kind {
+	case tokIdent:
+		return regexpAfterKeyword[prev.text]
+	case tokPunct:
+		switch prev.text {
+		case ")":
+			return l.afterCondition
+		case "]", "++", "--":
+			return false
+		}
+		return true
+	case tokTemplate:
+		return strings.HasSuffix(prev.text, "${")
+	}
+	return false
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// skipSpace skips whitespace and comments, recording line terminators.
+func (l *moduleLexer) skipSpace() error {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n' || c == '\r':
+			l.nl = true
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\v' || c == '\f':
+			l.pos++
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			if r == '\u2028' || r == '\u2029' {
+				l.nl = true
+			} else if !unicode.IsSpace(r) && r != '\ufeff' {
+				return nil
+			}
+			l.pos += size
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			end := strings.IndexAny(l.src[l.pos:], "\r\n")
+			if end < 0 {
+				l.pos = len(l.src)
+			} else {
+				l.pos += end
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				return l.errorf(l.pos, "unterminated comment")
+			}
+			if strings.ContainsAny(l.src[l.pos:l.pos+2+end], "\r\n") {
+				l.nl = true
+			}
+			l.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+var moduleLexerPuncts = []string{
+	">>>=", "...", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "**", "<<", ">>",
+}
+
+func (l *moduleLexer) next() (token, error) {
+	if err := l.skipSpace(); err != nil {
+		return token{}, err
+	}
+	tok := token{start: l.pos, nl: l.nl}
+	l.nl = false
+	if l.pos >= len(l.src) {
+		if len(l.braces) > 0 {
+			return tok, l.errorf(l.pos, "unexpected end of input")
+		}
+		tok.kind = tokEOF
+		return tok, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case c == '"' || c == '\'':
+		if err := l.skipString(c); err != nil {
+			return tok, err
+		}
+		tok.kind = tokString
+	case c == '`':
+		l.pos++
+		if err := l.skipTemplate(tok.start); err != nil {
+			return tok, err
+		}
+		tok.kind = tokTemplate
+	case '0' <= c && c <= '9' || c == '.' && l.pos+1 < len(l.src) && '0' <= l.src[l.pos+1] && l.src[l.pos+1] <= '9':
+		for l.pos < len(l.src) {
+			c := l.src[l.pos]
+			if (c == '+' || c == '-') && (l.src[l.pos-1] == 'e' || l.src[l.pos-1] == 'E') &&
+				!strings.HasPrefix(l.src[tok.start:], "0x") && !strings.HasPrefix(l.src[tok.start:], "0X") {
+				l.pos++
+			} else if isIdentByte(c) || c == '.' {
+				l.pos++
+			} else {
+				break
+			}
+		}
+		tok.kind = tokNumber
+	case isIdentByte(c) || c == '\\':
+		for l.pos < len(l.src) && (isIdentByte(l.src[l.pos]) || l.src[l.pos] == '\\') {
+			l.pos++
+		}
+		tok.kind = tokIdent
+	case c == '/' && l.regexpAllowed():
+		if err := l.skipRegExp(); err != nil {
+			return tok, err
+		}
+		tok.kind = tokRegExp
+	case c == '(':
+		n := len(l.toks)
+		if n > 1 && l.toks[n-1].text == "await" && l.toks[n-2].text == "for" {
+			n-- // for await (...)
+		}
+		isCondition := n > 0 && l.toks[n-1].kind == tokIdent && conditionKeyword[l.toks[n-1].text] &&
+			(n < 2 || !isMemberAccess(l.toks[n-2]))
+		l.parens = append(l.parens, isCondition)
+		l.pos++
+		tok.kind = tokPunct
+	case c == ')':
+		l.afterCondition = false
+		if n := len(l.parens); n > 0 {
+			l.afterCondition = l.parens[n-1]
+			l.parens = l.parens[:n-1]
+		}
+		l.pos++
+		tok.kind = tokPunct
+	case c == '{':
+		l.braces = append(l.braces, 'b')
+		l.pos++
+		tok.kind = tokPunct
+	case c == '}':
+		if len(l.braces) == 0 {
+			return tok, l.errorf(l.pos, "unexpected '}'")
+		}
+		top := l.braces[len(l.braces)-1]
+		l.braces = l.braces[:len(l.braces)-1]
+		l.pos++
+		if top == 't' {
+			if err := l.skipTemplate(tok.start); err != nil {
+				return tok, err
+			}
+			tok.kind = tokTemplate
+		} else {
+			tok.kind = tokPunct
+		}
+	default:
+		tok.kind = tokPunct
+		l.pos++
+		for _, p := range moduleLexerPuncts {
+			if strings.HasPrefix(l.src[tok.start:], p) {
+				l.pos = tok.start + len(p)
+				break
+			}
+		}
+	}
+	tok.end = l.pos
+	tok.text = l.src[tok.start:tok.end]
+	return tok, nil
+}
+
+func (l *moduleLexer) skipString(quote byte) error {
+	start := l.pos
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos++
+		case quote:
+			l.pos++
+			return nil
+		case '\n':
+			return l.errorf(start, "unterminated string literal")
+		}
+	}
+	return l.errorf(start, "unterminated string literal")
+}
+
+// skipTemplate skips template characters up to the closing backquote or the
+// start of a substitution, whose closing brace resumes the template. start is
+// the offset of the backquote or brace the characters follow.
+func (l *moduleLexer) skipTemplate(start int) error {
+	for ; l.pos < len(l.src); l.pos++ {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos++
+		case '`':
+			l.pos++
+			return nil
+		case '$':
+			if l.pos+1 < len(l.src) && l.src[l.pos+1] == '{' {
+				l.pos += 2
+				l.braces = append(l.braces, 't')
+				return nil
+			}
+		}
+	}
+	return l.errorf(start, "unterminated template literal")
+}
+
+func (l *moduleLexer) skipRegExp() error {
+	start := l.pos
+	inClass := false
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '\n':
+			return l.errorf(start, "unterminated regular expression literal")
+		case '/':
+			if !inClass {
+				for l.pos++; l.pos < len(l.src) && isIdentByte(l.src[l.pos]); l.pos++ {
+				}
+				return nil
+			}
+		}
+	}
+	return l.errorf(start, "unterminated regular expression literal")
+}
+
+//=========================================================
+// Rewriter
+//
+
+type moduleEdit struct {
+	start, end int
+	text       string
+}
+
+type moduleRewriter struct {
+	src   string
+	toks  []token
+	i     int
+	edits []moduleEdit
+
+	// exports maps exported names (as JavaScript string literals) to the
+	// expressions they read.
+	exports     []string
+	exportExprs map[string]string
+	// prologue holds the statements that load dependencies, in order.
+	prologue []string
+	deps     int
+}
+
+// rewriteModule returns the function body that evaluates the module src.
+func rewriteModule(src string) (string, error) {
+	// A hashbang line is only allowed at the very start of the source.
+	if strings.HasPrefix(src, "#!") {
+		end := strings.IndexAny(src, "\r\n")
+		if end < 0 {
+			end = len(src)
+		}
+		src = strings.Repeat(" ", end) + src[end:]
+	}
+
+	toks, err := lexModule(src)
+	if err != nil {
+		return "", err
+	}
+	r := &moduleRewriter{src: src, toks: toks, exportExprs: make(map[string]string)}
+	if err := r.rewrite(); err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if len(r.exports) > 0 {
+		out.WriteString("__gojs_module.exports({")
+		for i, name := range r.exports {
+			if i > 0 {
+				out.WriteString(", ")
+			}
+			fmt.Fprintf(&out, "%s: function () { return %s; }", name, r.exportExprs[name])
+		}
+		out.WriteString("}); ")
+	}
+	for _, stmt := range r.prologue {
+		out.WriteString(stmt)
+		out.WriteString(" ")
+	}
+	pos := 0
+	for _, e := range r.edits {
+		out.WriteString(src[pos:e.start])
+		out.WriteString(e.text)
+		out.WriteString(lineBreaks(src[e.start:e.end]))
+		pos = e.end
+	}
+	out.WriteString(src[pos:])
+	return out.String(), nil
+}
+
+// lineBreaks returns the line terminators in s, so that removed text keeps
+// the line numbering of what follows.
+func lineBreaks(s string) string {
+	return strings.Repeat("\n", strings.Count(s, "\n"))
+}
+
+func (r *moduleRewriter) peek(n int) token {
+	if r.i+n < len(r.toks) {
+		return r.toks[r.i+n]
+	}
+	return token{kind: tokEOF, start: len(r.src), end: len(r.src)}
+}
+
+func (r *moduleRewriter) errorf(tok token, format string, args ...interface{}) error {
+	l := &moduleLexer{src: r.src}
+	return l.errorf(tok.start, format, args...)
+}
+
+func (r *moduleRewriter) expect(text string) (token, error) {
+	tok := r.peek(0)
+	if tok.text != text || tok.kind == tokString {
+		return tok, r.errorf(tok, "expected '%s' but found '%s'", text, tok.text)
+	}
+	r.i++
+	return tok, nil
+}
+
+func (r *moduleRewriter) edit(start, end int, text string) {
+	r.edits = append(r.edits, moduleEdit{start, end, text})
+}
+
+func (r *moduleRewriter) addExport(tok token, name, expr string) error {
+	if _, ok := r.exportExprs[name]; ok {
+		return r.errorf(tok, "duplicate export %s", name)
+	}
+	r.exports = append(r.exports, name)
+	r.exportExprs[name] = expr
+	return nil
+}
+
+// require adds a prologue statement loading the module specifier (a string
+// literal) and returns the variable holding its namespace.
+func (r *moduleRewriter) require(specifier string) string {
+	v := fmt.Sprintf("__gojs_m%d", r.deps)
+	r.deps++
+	r.prologue = append(r.prologue, fmt.Sprintf("var %s = __gojs_module.require(%s);", v, specifier))
+	return v
+}
+
+func (r *moduleRewriter) rewrite() error {
+	depth := 0
+	for r.i < len(r.toks) {
+		tok := r.toks[r.i]
+		if tok.kind == tokPunct {
+			switch tok.text {
+			case "{", "(", "[":
+				depth++
+			case "}", ")", "]":
+				depth--
+			}
+		}
+		if tok.kind == tokTemplate {
+			// Substitutions open and close braces inside template tokens.
+			if strings.HasSuffix(tok.text, "${") {
+				depth++
+			}
+			if strings.HasPrefix(tok.text, "}") {
+				depth--
+			}
+		}
+		if tok.kind != tokIdent || r.i > 0 && isMemberAccess(r.toks[r.i-1]) {
+			r.i++
+			continue
+		}
+
+		var err error
+		switch {
+		case tok.text == "import" && r.peek(1).text == "(":
+			if r.isMethodDefinition() {
+				r.i++
+				continue
+			}
+			r.edit(tok.start, tok.end, "__gojs_module.import")
+			r.i++
+		case tok.text == "import" && r.peek(1).text == "." && r.peek(2).text == "meta":
+			r.edit(tok.start, r.peek(2).end, "__gojs_module.meta")
+			r.i += 3
+		case tok.text == "import" && depth == 0:
+			err = r.importDeclaration()
+		case tok.text == "export" && depth == 0:
+			err = r.exportDeclaration()
+		default:
+			r.i++
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isMemberAccess(prev token) bool {
+	return prev.kind == tokPunct && (prev.text == "." || prev.text == "?." || prev.text == "#")
+}
+
+// isMethodDefinition reports whether the import token at r.i names a method,
+// as in "class C { import() {} }", rather than starting a dynamic import.
+func (r *moduleRewriter) isMethodDefinition() bool {
+	depth := 0
+	for j := r.i + 1; j < len(r.toks); j++ {
+		switch r.toks[j].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return j+1 < len(r.toks) && r.toks[j+1].text == "{" && !r.toks[j+1].nl
+			}
+		}
+	}
+	return false
+}
+
+// endStatement consumes an optional semicolon and returns the end offset of
+// the statement.
+func (r *moduleRewriter) endStatement() int {
+	if tok := r.peek(0); tok.kind == tokPunct && tok.text == ";" {
+		r.i++
+		return tok.end
+	}
+	return r.toks[r.i-1].end
+}
+
+// skipAttributes skips an import attributes clause such as
+// `with { type: "json" }`.
+func (r *moduleRewriter) skipAttributes() error {
+	if tok := r.peek(0); tok.kind != tokIdent || (tok.text != "with" && tok.text != "assert") || tok.nl {
+		return nil
+	}
+	r.i++
+	if _, err := r.expect("{"); err != nil {
+		return err
+	}
+	for r.peek(0).text != "}" {
+		if r.peek(0).kind == tokEOF {
+			return r.errorf(r.peek(0), "unterminated import attributes")
+		}
+		r.i++
+	}
+	r.i++
+	return nil
+}
+
+func (r *moduleRewriter) moduleSpecifier() (string, error) {
+	if _, err := r.expect("from"); err != nil {
+		return "", err
+	}
+	tok := r.peek(0)
+	if tok.kind != tokString {
+		return "", r.errorf(tok, "expected a module specifier string")
+	}
+	r.i++
+	return tok.text, r.skipAttributes()
+}
+
+// moduleExportName reads an identifier or string literal naming an export and
+// returns it as a JavaScript string literal.
+func (r *moduleRewriter) moduleExportName() (string, token, error) {
+	tok := r.peek(0)
+	switch tok.kind {
+	case tokIdent:
+		r.i++
+		return strconv.Quote(tok.text), tok, nil
+	case tokString:
+		r.i++
+		return tok.text, tok, nil
+	}
+	return "", tok, r.errorf(tok, "expected an export name but found '%s'", tok.text)
+}
+
+func (r *moduleRewriter) bindingIdentifier() (string, error) {
+	tok := r.peek(0)
+	if tok.kind != tokIdent {
+		return "", r.errorf(tok, "expected an identifier but found '%s'", tok.text)
+	}
+	r.i++
+	return tok.text, nil
+}
+
+type importBinding struct {
+	local string
+	// name is the imported name as a string literal, or empty for a
+	// namespace import.
+	name string
+}
+
+func (r *moduleRewriter) importDeclaration() error {
+	start := r.peek(0).start
+	r.i++
+
+	// import "specifier";
+	if tok := r.peek(0); tok.kind == tokString {
+		r.i++
+		if err := r.skipAttributes(); err != nil {
+			return err
+		}
+		r.require(tok.text)
+		r.edit(start, r.endStatement(), "")
+		return nil
+	}
+
+	var bindings []importBinding
+	if tok := r.peek(0); tok.kind == tokIdent {
+		r.i++
+		bindings = append(bindings, importBinding{tok.text, `"default"`})
+	}
+	if len(bindings) == 0 || r.peek(0).text == "," {
+		if len(bindings) > 0 {
+			r.i++
+		}
+		if err := r.importClause(&bindings); err != nil {
+			return err
+		}
+	}
+
+	specifier, err := r.moduleSpecifier()
+	if err != nil {
+		return err
+	}
+	ns := r.require(specifier)
+	for _, b := range bindings {
+		if b.name == "" {
+			r.prologue = append(r.prologue, fmt.Sprintf("__gojs_module.binding(%q, %s);", b.local, ns))
+		} else {
+			r.prologue = append(r.prologue, fmt.Sprintf("__gojs_module.binding(%q, %s, %s, %s);", b.local, ns, b.name, specifier))
+		}
+	}
+	r.edit(start, r.endStatement(), "")
+	return nil
+}
+
+// importClause reads a namespace import or a list of named imports.
+func (r *moduleRewriter) importClause(bindings *[]importBinding) error {
+	switch r.peek(0).text {
+	case "*":
+		r.i++
+		if _, err := r.expect("as"); err != nil {
+			return err
+		}
+		local, err := r.bindingIdentifier()
+		if err != nil {
+			return err
+		}
+		*bindings = append(*bindings, importBinding{local, ""})
+	case "{":
+		r.i++
+		for r.peek(0).text != "}" {
+			name, tok, err := r.moduleExportName()
+			if err != nil {
+				return err
+			}
+			local := tok.text
+			if r.peek(0).text == "as" {
+				r.i++
+				if local, err = r.bindingIdentifier(); err != nil {
+					return err
+				}
+			} else if tok.kind != tokIdent {
+				return r.errorf(tok, "string import name requires a local binding")
+			}
+			*bindings = append(*bindings, importBinding{local, name})
+			if r.peek(0).text != "," {
+				break
+			}
+			r.i++
+		}
+		if _, err := r.expect("}"); err != nil {
+			return err
+		}
+	default:
+		return r.errorf(r.peek(0), "unexpected '%s' in import declaration", r.peek(0).text)
+	}
+	return nil
+}
+
+func (r *moduleRewriter) exportDeclaration() error {
+	exportTok := r.peek(0)
+	start := exportTok.start
+	r.i++
+
+	tok := r.peek(0)
+	switch {
+	case tok.text == "*":
+		// export * from "m"; export * as ns from "m";
+		r.i++
+		var name string
+		var nameTok token
+		if r.peek(0).text == "as" {
+			r.i++
+			var err error
+			if name, nameTok, err = r.moduleExportName(); err != nil {
+				return err
+			}
+		}
+		specifier, err := r.moduleSpecifier()
+		if err != nil {
+			return err
+		}
+		ns := r.require(specifier)
+		if name != "" {
+			if err := r.addExport(nameTok, name, ns); err != nil {
+				return err
+			}
+		} else {
+			r.prologue = append(r.prologue, fmt.Sprintf("__gojs_module.reexport(%s);", ns))
+		}
+		r.edit(start, r.endStatement(), "")
+
+	case tok.text == "{":
+		// export { a, b as c }; export { a as b } from "m";
+		r.i++
+		type spec struct {
+			local    token
+			exported string
+		}
+		var specs []spec
+		for r.peek(0).text != "}" {
+			_, local, err := r.moduleExportName()
+			if err != nil {
+				return err
+			}
+			exported := strconv.Quote(local.text)
+			if local.kind == tokString {
+				exported = local.text
+			}
+			if r.peek(0).text == "as" {
+				r.i++
+				if exported, _, err = r.moduleExportName(); err != nil {
+					return err
+				}
+			}
+			specs = append(specs, spec{local, exported})
+			if r.peek(0).text != "," {
+				break
+			}
+			r.i++
+		}
+		if _, err := r.expect("}"); err != nil {
+			return err
+		}
+		if r.peek(0).text == "from" {
+			specifier, err := r.moduleSpecifier()
+			if err != nil {
+				return err
+			}
+			ns := r.require(specifier)
+			for _, s := range specs {
+				name := s.local.text
+				if s.local.kind == tokIdent {
+					name = strconv.Quote(name)
+				}
+				if err := r.addExport(s.local, s.exported, ns+"["+name+"]"); err != nil {
+					return err
+				}
+			}
+		} else {
+			for _, s := range specs {
+				if s.local.kind != tokIdent {
+					return r.errorf(s.local, "string export name requires a 'from' clause")
+				}
+				if err := r.addExport(s.local, s.exported, s.local.text); err != nil {
+					return err
+				}
+			}
+		}
+		r.edit(start, r.endStatement(), "")
+
+	case tok.text == "default":
+		return r.exportDefault(start)
+
+	case tok.text == "var" || tok.text == "let" || tok.text == "const":
+		r.edit(start, tok.start, "")
+		r.i++
+		names, err := r.declarationNames()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err := r.addExport(name, strconv.Quote(name.text), name.text); err != nil {
+				return err
+			}
+		}
+
+	case tok.text == "function" || tok.text == "class" || tok.text == "async" && r.peek(1).text == "function" && !r.peek(1).nl:
+		r.edit(start, tok.start, "")
+		for r.peek(0).text != "function" && r.peek(0).text != "class" {
+			r.i++
+		}
+		r.i++
+		if r.peek(0).text == "*" {
+			r.i++
+		}
+		name := r.peek(0)
+		if name.kind != tokIdent {
+			return r.errorf(name, "exported declaration requires a name")
+		}
+		r.i++
+		return r.addExport(name, strconv.Quote(name.text), name.text)
+
+	default:
+		return r.errorf(tok, "unexpected '%s' after export", tok.text)
+	}
+	return nil
+}
+
+func (r *moduleRewriter) exportDefault(start int) error {
+	defaultTok := r.peek(0)
+	r.i++
+	tok := r.peek(0)
+
+	isFunction := tok.text == "function" || tok.text == "async" && r.peek(1).text == "function" && !r.peek(1).nl
+	if isFunction || tok.text == "class" {
+		j := 1
+		if tok.text == "async" {
+			j++
+		}
+		if r.peek(j).text == "*" {
+			j++
+		}
+		name := r.peek(j)
+		if name.kind == tokIdent && name.text != "extends" {
+			// export default function f() {} keeps the declaration.
+			r.edit(start, tok.start, "")
+			r.i += j + 1
+			return r.addExport(defaultTok, `"default"`, name.text)
+		}
+		// Anonymous declarations are given a name to export.
+		r.edit(start, tok.start, "")
+		r.edit(name.start, name.start, "__gojs_default ")
+		r.i += j
+		return r.addExport(defaultTok, `"default"`, "__gojs_default")
+	}
+
+	r.edit(start, defaultTok.end, "var __gojs_default =")
+	return r.addExport(defaultTok, `"default"`, "__gojs_default")
+}
+
+// declarationNames reads the declarators of a variable declaration and
+// returns the identifiers they bind.
+func (r *moduleRewriter) declarationNames() ([]token, error) {
+	var names []token
+	for {
+		if err := r.bindingTarget(&names); err != nil {
+			return nil, err
+		}
+		if r.peek(0).text == "=" {
+			r.i++
+			r.skipExpression(true)
+		}
+		if r.peek(0).text != "," {
+			break
+		}
+		r.i++
+	}
+	return names, nil
+}
+
+// bindingTarget reads an identifier or a destructuring pattern.
+func (r *moduleRewriter) bindingTarget(names *[]token) error {
+	tok := r.peek(0)
+	switch {
+	case tok.kind == tokIdent:
+		r.i++
+		*names = append(*names, tok)
+		return nil
+	case tok.text == "{":
+		r.i++
+		for r.peek(0).text != "}" {
+			if r.peek(0).text == "..." {
+				r.i++
+				if err := r.bindingTarget(names); err != nil {
+					return err
+				}
+			} else {
+				key := r.peek(0)
+				if key.text == "[" {
+					r.skipBalanced()
+				} else {
+					r.i++
+				}
+				if r.peek(0).text == ":" {
+					r.i++
+					if err := r.bindingTarget(names); err != nil {
+						return err
+					}
+				} else if key.kind == tokIdent {
+					*names = append(*names, key)
+				} else {
+					return r.errorf(key, "unexpected '%s' in object pattern", key.text)
+				}
+			}
+			if r.peek(0).text == "=" {
+				r.i++
+				r.skipExpression(false)
+			}
+			if r.peek(0).text != "," {
+				break
+			}
+			r.i++
+		}
+		_, err := r.expect("}")
+		return err
+	case tok.text == "[":
+		r.i++
+		for r.peek(0).text != "]" {
+			if r.peek(0).text == "," {
+				r.i++
+				continue
+			}
+			if r.peek(0).text == "..." {
+				r.i++
+			}
+			if err := r.bindingTarget(names); err != nil {
+				return err
+			}
+			if r.peek(0).text == "=" {
+				r.i++
+				r.skipExpression(false)
+			}
+			if r.peek(0).text != "," {
+				break
+			}
+			r.i++
+		}
+		_, err := r.expect("]")
+		return err
+	}
+	return r.errorf(tok, "unexpected '%s' in declaration", tok.text)
+}
+
+// skipBalanced skips a bracketed token sequence starting at r.i.
+func (r *moduleRewriter) skipBalanced() {
+	depth := 0
+	for r.i < len(r.toks) {
+		tok := r.toks[r.i]
+		r.i++
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		}
+		if strings.HasSuffix(tok.text, "${") && tok.kind == tokTemplate {
+			depth++
+		}
+		if strings.HasPrefix(tok.text, "}") && tok.kind == tokTemplate {
+			depth--
+		}
+		if depth <= 0 {
+			return
+		}
+	}
+}
+
+// skipExpression skips an initializer up to a comma or closing bracket at its
+// own nesting level. At statement level it also stops at a semicolon or at a
+// line break where automatic semicolon insertion ends the statement.
+func (r *moduleRewriter) skipExpression(statement bool) {
+	first := true
+	for r.i < len(r.toks) {
+		tok := r.toks[r.i]
+		if tok.kind == tokPunct {
+			switch tok.text {
+			case ",", ")", "]", "}", ";":
+				return
+			case "(", "[", "{":
+				r.skipBalanced()
+				first = false
+				continue
+			}
+		}
+		if tok.kind == tokTemplate && strings.HasSuffix(tok.text, "${") {
+			r.skipBalanced()
+			first = false
+			continue
+		}
+		if statement && tok.nl && !first && !r.continuesExpression(tok) {
+			return
+		}
+		first = false
+		r.i++
+	}
+}
+
+// continuesExpression reports whether tok, which follows a line break,
+// continues the expression before it rather than starting a new statement.
+func (r *moduleRewriter) continuesExpression(tok token) bool {
+	prev := r.toks[r.i-1]
+	if prev.kind == tokPunct && prev.text != ")" && prev.text != "]" && prev.text != "}" &&
+		prev.text != "++" && prev.text != "--" {
+		return true
+	}
+	switch tok.kind {
+	case tokPunct:
+		switch tok.text {
+		case "!", "~", "++", "--", "{":
+			return false
+		}
+		return true
+	case tokIdent:
+		return tok.text == "in" || tok.text == "instanceof"
+	case tokTemplate:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package gojs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriteModule(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{"import x from './a';", []string{
+			`var __gojs_m0 = __gojs_module.require('./a');`,
+			`__gojs_module.binding("x", __gojs_m0, "default", './a');`}},
+		{"import d, {y as z, w} from './a'", []string{
+			`__gojs_module.binding("d", __gojs_m0, "default", './a');`,
+			`__gojs_module.binding("z", __gojs_m0, "y", './a');`,
+			`__gojs_module.binding("w", __gojs_m0, "w", './a');`}},
+		{"import * as ns from \"./b\"", []string{`__gojs_module.binding("ns", __gojs_m0);`}},
+		{"import './c';", []string{`__gojs_module.require('./c');`}},
+		{"export const a = 1, {b, c: [d, e = 2]} = obj", []string{
+			`"a": function () { return a; }`, `"b": function () { return b; }`,
+			`"d": function () { return d; }`, `"e": function () { return e; }`,
+			"const a = 1, {b, c: [d, e = 2]} = obj"}},
+		{"export function f() {}\nexport class C {}", []string{
+			`"f": function () { return f; }`, `"C": function () { return C; }`,
+			"function f() {}\nclass C {}"}},
+		{"export default 1 + 2;", []string{
+			`"default": function () { return __gojs_default; }`,
+			"var __gojs_default = 1 + 2;"}},
+		{"export default function () {}", []string{"function __gojs_default () {}"}},
+		{"export default class Named {}", []string{
+			`"default": function () { return Named; }`, "class Named {}"}},
+		{"let a, b; export {a, b as c};", []string{
+			`"a": function () { return a; }`, `"c": function () { return b; }`}},
+		{"export * from './e';", []string{`__gojs_module.reexport(__gojs_m0);`}},
+		{"export * as E from './e';", []string{`"E": function () { return __gojs_m0; }`}},
+		{"export {q as r} from './f';", []string{`"r": function () { return __gojs_m0["q"]; }`}},
+		{"f(import('./d'), import.meta.url)", []string{"f(__gojs_module.import('./d'), __gojs_module.meta.url)"}},
+		{"class A { import() {} }; o.import(1)", []string{"class A { import() {} }; o.import(1)"}},
+		{"var s = 'import x from \"y\"', r = /export {}/, t = `${'import(\"z\")'}`;", []string{
+			`var s = 'import x from "y"', r = /export {}/, t = ` + "`${'import(\"z\")'}`;"}},
+		{"if (x) /import(y)/.test(s); export {}", []string{"if (x) /import(y)/.test(s);"}},
+		{"for await (const x of y) /import(y)/.test(x)", []string{"/import(y)/.test(x)"}},
+		{"var q = f(x) / import.meta.n / 2", []string{"f(x) / __gojs_module.meta.n / 2"}},
+	}
+
+	for _, test := range tests {
+		out, err := rewriteModule(test.src)
+		if err != nil {
+			t.Errorf("rewriteModule(%q): %v", test.src, err)
+			continue
+		}
+		for _, want := range test.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("rewriteModule(%q) = %q, want it to contain %q", test.src, out, want)
+			}
+		}
+	}
+}
+
+func TestRewriteModuleKeepsLines(t *testing.T) {
+	src := "#!/usr/bin/env gojs\nimport {\n  a,\n  b\n} from './a'\nexport {\n  a\n}\nthrow new Error(b)\n"
+	out, err := rewriteModule(src)
+	if err != nil {
+		t.Fatalf("rewriteModule: %v", err)
+	}
+	lines := strings.Split(out, "\n")
+	if len(lines) != strings.Count(src, "\n")+1 {
+		t.Fatalf("rewritten module has %d lines, want %d:\n%s", len(lines), strings.Count(src, "\n")+1, out)
+	}
+	if lines[8] != "throw new Error(b)" {
+		t.Errorf("line 9 of rewritten module is %q", lines[8])
+	}
+}
+
+func TestRewriteModuleErrors(t *testing.T) {
+	tests := []struct {
+		src, err string
+	}{
+		{"import {a from './a'}", "1:11: SyntaxError: expected '}' but found 'from'"},
+		{"import x './a'", "1:10: SyntaxError: expected 'from' but found ''./a''"},
+		{"export const a = 1\nexport {a}", "2:9: SyntaxError: duplicate export \"a\""},
+		{"export default function () {}\nexport default 1", "2:8: SyntaxError: duplicate export \"default\""},
+		{"export {'a b'}", "1:9: SyntaxError: string export name requires a 'from' clause"},
+		{"var s = 'abc", "1:9: SyntaxError: unterminated string literal"},
+		{"f(`abc", "1:3: SyntaxError: unterminated template literal"},
+	}
+
+	for _, test := range tests {
+		_, err := rewriteModule(test.src)
+		if err == nil {
+			t.Errorf("rewriteModule(%q) did not fail", test.src)
+		} else if err.Error() != test.err {
+			t.Errorf("rewriteModule(%q) error %q, want %q", test.src, err, test.err)
+		}
+	}
+}
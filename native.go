@@ -9,8 +9,10 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math/big"
 	"reflect"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
@@ -18,6 +20,8 @@ type object_data struct {
 	typ    reflect.Type
 	val    reflect.Value
 	method int
+	// results names the results of native functions returned as objects.
+	results []string
 }
 
 var (
@@ -103,18 +107,50 @@ func (ctx *Context) reflectToJSValue(value reflect.Value) *Value {
 	if value.Type() == reflect.TypeOf((*Object)(nil)) {
 		// Type is already a JavaScriptCore object
 		// nearly there
+		if value.IsNil() {
+			return ctx.NewNullValue()
+		}
 		return value.Interface().(*Object).ToValue()
 	}
 
+	// Times become Dates, and durations numbers of milliseconds.
+	switch value.Type() {
+	case timeType:
+		r, err := ctx.NewDateFromTime(value.Interface().(time.Time))
+		if err != nil {
+			panic(err)
+		}
+		return r.ToValue()
+	case durationType:
+		return ctx.newDurationValue(time.Duration(value.Int()))
+	case bigIntType:
+		if value.IsNil() {
+			return ctx.NewNullValue()
+		}
+		r, err := ctx.NewBigInt(value.Interface().(*big.Int))
+		if err != nil {
+			panic(err)
+		}
+		return r
+	}
+
 	// Handle simple types directly.  These can be identified by their
 	// types in the package 'reflect'.
 	switch value.Kind() {
-	case (reflect.Int):
-		r := value.Int()
-		return ctx.NewNumberValue(float64(r))
-	case (reflect.Uint):
-		r := value.Uint()
-		return ctx.NewNumberValue(float64(r))
+	case (reflect.Bool):
+		return ctx.NewBooleanValue(value.Bool())
+	case (reflect.Int), (reflect.Int8), (reflect.Int16), (reflect.Int32), (reflect.Int64):
+		r, err := ctx.newIntValue(value.Int())
+		if err != nil {
+			panic(err)
+		}
+		return r
+	case (reflect.Uint), (reflect.Uint8), (reflect.Uint16), (reflect.Uint32), (reflect.Uint64), (reflect.Uintptr):
+		r, err := ctx.newUintValue(value.Uint())
+		if err != nil {
+			panic(err)
+		}
+		return r
 	case (reflect.Float64), (reflect.Float32):
 		r := value.Float()
 		return ctx.NewNumberValue(r)
@@ -122,11 +158,33 @@ func (ctx *Context) reflectToJSValue(value reflect.Value) *Value {
 		r := value.String()
 		return ctx.NewStringValue(r)
 	case (reflect.Func):
+		// Sequences become iterators rather than functions.
+		if ok, n := seqType(value.Type()); ok {
+			return ctx.newSeqIterator(value, n)
+		}
 		r := value.Interface()
 		return ctx.NewFunctionWithNative(r).ToValue()
-	//case (reflect.Struct):
-	//	r := value.Interface()
-	//	return ctx.NewNativeObject(r).ToValue()
+	case (reflect.Chan):
+		if value.Type().ChanDir()&reflect.RecvDir != 0 {
+			return ctx.newChanIterator(value)
+		}
+	case (reflect.Slice):
+		// Slices of numbers become typed arrays
+		if _, ok := sliceTypedArrayType(value.Type()); ok {
+			if value.IsNil() {
+				return ctx.NewNullValue()
+			}
+			r, err := ctx.newTypedArrayFromSlice(value)
+			if err != nil {
+				panic(err)
+			}
+			return r.ToValue()
+		}
+	case (reflect.Interface):
+		if value.IsNil() {
+			return ctx.NewNullValue()
+		}
+		return ctx.reflectToJSValue(value.Elem())
 	case (reflect.Ptr):
 		if value.IsNil() {
 			return ctx.NewNullValue()
@@ -151,6 +209,9 @@ func (ctx *Context) reflectToJSValue(value reflect.Value) *Value {
 func panicArgToJSString(ctx *Context, r interface{}) *Value {
 	var msg string
 	switch r := r.(type) {
+	case *errorValue:
+		// Rethrow JavaScript exceptions unchanged.
+		return ctx.newValue(r.ref)
 	case error:
 		msg = r.Error()
 	case string:
@@ -161,31 +222,43 @@ func panicArgToJSString(ctx *Context, r interface{}) *Value {
 	return ctx.NewStringValue(msg)
 }
 
-func (ctx *Context) jsValuesToReflect(param []*Value) []reflect.Value {
-	ret := make([]reflect.Value, len(param))
-
-	for index, item := range param {
-		var goval interface{}
-		log.Println(index, item)
-
-		switch item.Type() {
-		case TypeBoolean:
-			goval = item.ToBoolean()
-		case TypeNumber:
-			goval = item.ToNumberOrDie()
-		case TypeString:
-			goval = item.ToStringOrDie()
-		default:
-			panic("Parameter can not be converted to Go native type.")
-		}
-
-		ret[index] = reflect.ValueOf(goval)
+// jsValueToGoType converts value for Go types that JavaScript values map to
+// whatever their type: typed arrays to slices of numbers, Dates to
+// time.Time, numbers of milliseconds to time.Duration, and numbers and
+// BigInts to integers and *big.Int. It returns false if typ is not one of
+// these types, or value does not fit it.
+func jsValueToGoType(value *Value, typ reflect.Type) (r reflect.Value, ok bool, err error) {
+	isInteger := value.IsNumber() || value.IsBigInt()
+	switch {
+	case typ == timeType:
+		t, err := value.Time()
+		return reflect.ValueOf(t), true, err
+	case typ == durationType && value.IsNumber():
+		d, err := value.toDuration()
+		return reflect.ValueOf(d), true, err
+	case typ == bigIntType && isInteger:
+		x, err := value.BigInt()
+		return reflect.ValueOf(x), true, err
+	case (isIntKind(typ.Kind()) || isUintKind(typ.Kind())) && isInteger:
+		r, err = jsValueToInt(value, typ)
+		return r, true, err
 	}
-
-	return ret
+	// Plain arrays convert to these slices element by element, as to others.
+	if _, isSlice := sliceTypedArrayType(typ); isSlice && value.IsObject() && value.TypedArrayType() != NotTypedArray {
+		r, err = typedArrayToSlice(value, typ)
+		return r, true, err
+	}
+	return reflect.Value{}, false, nil
 }
 
 func setNativeFieldFromJSValue(field reflect.Value, ctx *Context, value *Value) (err error) {
+	if r, ok, err := jsValueToGoType(value, field.Type()); ok {
+		if err == nil {
+			field.Set(r)
+		}
+		return err
+	}
+
 	switch field.Kind() {
 	case reflect.String:
 		var str string
@@ -262,7 +335,7 @@ func (ctx *Context) NewFunctionWithCallback(callback GoFunctionCallback) *Object
 	data := &object_data{
 		reflect.TypeOf(callback),
 		reflect.ValueOf(callback),
-		0}
+		0, nil}
 	register(data)
 
 	ret := C.JSObjectMake(ctx.ref, nativecallback, unsafe.Pointer(data))
@@ -291,51 +364,77 @@ func nativecallback_CallAsFunction_go(data_ptr unsafe.Pointer, rawCtx C.JSContex
 // Native Function
 //---------------------------------------------------------
 
+// NewFunctionWithNative creates a JavaScript function calling the Go
+// function fn. Its arguments are converted to the parameters of fn as
+// described for callArgs: leading parameters of type *Context, This,
+// CallInfo and context.Context are injected, and a last parameter of type
+// Rest takes the surplus arguments unconverted. Its results are converted
+// as described for callResults: multiple results become an array, and a
+// last error result is thrown rather than returned.
 func (ctx *Context) NewFunctionWithNative(fn interface{}) *Object {
+	return ctx.newNativeFunction(fn, nil)
+}
+
+// NewFunctionWithNamedResults is like NewFunctionWithNative, but returns
+// the results of fn, other than a last error, as an object with a property
+// for each of names, such as { value: 1, ok: true } for a function
+// returning (int, bool) with names "value" and "ok".
+func (ctx *Context) NewFunctionWithNamedResults(fn interface{}, names ...string) *Object {
+	if n := len(resultTypes(reflect.TypeOf(fn))); n != len(names) {
+		panic(fmt.Sprintf("Bad native function:  %d result names for %d results", len(names), n))
+	}
+	return ctx.newNativeFunction(fn, names)
+}
+
+func (ctx *Context) newNativeFunction(fn interface{}, results []string) *Object {
 	// Sanity checks on the function
-	if typ := reflect.TypeOf(fn); typ.NumOut() > 1 {
-		panic("Bad native function:  too many output parameters")
+	if reflect.TypeOf(fn).Kind() != reflect.Func {
+		panic("Bad native function:  not a function")
 	}
 
 	// Create Go-side registration
 	data := &object_data{
 		reflect.TypeOf(fn),
 		reflect.ValueOf(fn),
-		0}
+		0, results}
 	register(data)
 
 	ret := C.JSObjectMake(ctx.ref, nativefunction, unsafe.Pointer(data))
 	return ctx.newObject(ret)
 }
 
-func docall(ctx *Context, val reflect.Value, argumentCount uint, arguments unsafe.Pointer) *Value {
+// docall calls the native function val, named name, with the arguments of a
+// call from JavaScript, converted by callArgs, and converts its results with
+// callResults.
+func docall(ctx *Context, name string, val reflect.Value, results []string, function, thisObject C.JSObjectRef, argumentCount uint, arguments unsafe.Pointer) *Value {
 	// Step one, convert the JavaScriptCore array of arguments to
 	// an array of reflect.Values.
-	var in []reflect.Value
-	if argumentCount != 0 {
-		valarr := ctx.newGoValueArray(arguments, argumentCount)
-		log.Println("Converted pointer to go-style array", valarr)
-		log.Println("Converting to relfect.Value s")
-		in = ctx.jsValuesToReflect(valarr)
+	call := &CallInfo{
+		Context:   ctx,
+		Function:  ctx.newObject(function),
+		Arguments: ctx.newGoValueArray(arguments, argumentCount),
+	}
+	if thisObject != nil {
+		call.This = ctx.newObject(thisObject)
+	}
+	in, err := ctx.callArgs(name, val.Type(), call)
+	if err != nil {
+		panic(err)
 	}
-
-	log.Println("Converted arguments to native go reflect types. About to actually call the callback function...")
-
-	log.Println(in)
 
 	// Step two, perform the call
 	out := val.Call(in)
 
-	// Step three, convert the function return value back to JavaScriptCore
-	if len(out) == 0 {
-		return nil
+	// Step three, convert the function return values back to JavaScriptCore
+	ret, err := ctx.callResults(val.Type(), out, results)
+	if err != nil {
+		panic(err)
 	}
-	// len(out) should be equal to 1
-	return ctx.reflectToJSValue(out[0])
+	return ret
 }
 
 //export nativefunction_CallAsFunction_go
-func nativefunction_CallAsFunction_go(data_ptr unsafe.Pointer, rawCtx C.JSContextRef, _ unsafe.Pointer, _ unsafe.Pointer, argumentCount uint, arguments unsafe.Pointer, exception *C.JSValueRef) unsafe.Pointer {
+func nativefunction_CallAsFunction_go(data_ptr unsafe.Pointer, rawCtx C.JSContextRef, function unsafe.Pointer, thisObject unsafe.Pointer, argumentCount uint, arguments unsafe.Pointer, exception *C.JSValueRef) unsafe.Pointer {
 	ctx := NewContextFrom(RawContext(rawCtx))
 	defer func() {
 		if r := recover(); r != nil {
@@ -345,17 +444,8 @@ func nativefunction_CallAsFunction_go(data_ptr unsafe.Pointer, rawCtx C.JSContex
 
 	// recover the object
 	data := (*object_data)(data_ptr)
-	typ := data.typ
-	val := data.val
 
-	// Do the number of input parameters match?
-	if typ.NumIn() != int(argumentCount) {
-		panic("Incorrect number of function arguments")
-	}
-
-	log.Println("About to docall()!")
-
-	ret := docall(ctx, val, argumentCount, arguments)
+	ret := docall(ctx, funcName(data.val), data.val, data.results, C.JSObjectRef(function), C.JSObjectRef(thisObject), argumentCount, arguments)
 	if ret == nil {
 		return nil
 	}
@@ -366,18 +456,58 @@ func nativefunction_CallAsFunction_go(data_ptr unsafe.Pointer, rawCtx C.JSContex
 // Native Object
 //---------------------------------------------------------
 
+// NewNativeObject creates a JavaScript object wrapping the Go value obj,
+// whose fields and methods scripts can access. Pointers keep their
+// identity: while scripts refer to the wrapper of a pointer, wrapping it
+// again returns the same object.
 func (ctx *Context) NewNativeObject(obj interface{}) *Object {
 	// The obj must be a pointer to a struct
 	// TODO:  add error checking code
 
+	key, cached := wrapperKey(reflect.ValueOf(obj), "")
+	if cached {
+		if ret := ctx.cachedWrapper(key); ret != nil {
+			return ret
+		}
+	}
+
 	data := &object_data{
 		reflect.TypeOf(obj),
 		reflect.ValueOf(obj),
-		0}
+		0, nil}
 	register(data)
 
-	ret := C.JSObjectMake(ctx.ref, nativeobject, unsafe.Pointer(data))
-	return ctx.newObject(ret)
+	ret := ctx.newObject(C.JSObjectMake(ctx.ref, nativeobject, unsafe.Pointer(data)))
+	if cached {
+		ctx.cacheWrapper(key, ret)
+	}
+	return ret
+}
+
+// GoObject returns the Go value obj wraps if it was created by
+// NewNativeObject, or false for other objects.
+func (obj *Object) GoObject() (interface{}, bool) {
+	if !bool(C.JSValueIsObjectOfClass(obj.ctx.ref, C.JSValueRef(obj.ref), nativeobject)) {
+		return nil, false
+	}
+	data, ok := objects[uintptr(C.JSObjectGetPrivate(obj.ref))]
+	if !ok {
+		return nil, false
+	}
+	return data.val.Interface(), true
+}
+
+// unwrapNative converts r, the Go value a native object wraps, to typ: to
+// typ itself or an interface it implements, or, for pointers, to the type
+// they point to. Other types are a mismatch.
+func unwrapNative(r reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	switch {
+	case r.Type().AssignableTo(typ):
+		return r, nil
+	case r.Kind() == reflect.Ptr && !r.IsNil() && r.Elem().Type().AssignableTo(typ):
+		return r.Elem(), nil
+	}
+	return reflect.Value{}, fmt.Errorf("gojs: cannot convert native %v to %v", r.Type(), typ)
 }
 
 //export nativeobject_GetProperty_go
@@ -470,15 +600,27 @@ func nativeobject_ConvertToString_go(data_ptr, ctx, obj unsafe.Pointer) unsafe.P
 // Native Method
 //---------------------------------------------------------
 
+// newNativeMethod returns the method object of a native object, cached
+// like the wrappers of pointers so that each access returns the same one.
 func newNativeMethod(ctx *Context, obj *object_data, method int) *Object {
+	key, cached := wrapperKey(obj.val, obj.typ.Method(method).Name)
+	if cached {
+		if ret := ctx.cachedWrapper(key); ret != nil {
+			return ret
+		}
+	}
+
 	data := &object_data{
 		obj.typ,
 		obj.val,
-		method}
+		method, nil}
 	register(data)
 
-	ret := C.JSObjectMake(ctx.ref, nativemethod, unsafe.Pointer(data))
-	return ctx.newObject(ret)
+	ret := ctx.newObject(C.JSObjectMake(ctx.ref, nativemethod, unsafe.Pointer(data)))
+	if cached {
+		ctx.cacheWrapper(key, ret)
+	}
+	return ret
 }
 
 //export nativemethod_CallAsFunction_go
@@ -495,13 +637,12 @@ func nativemethod_CallAsFunction_go(data_ptr unsafe.Pointer, rawCtx C.JSContextR
 
 	// Get the method
 	method := data.val.Method(data.method)
-
-	// Do the number of input parameters match?
-	if method.Type().NumIn() != int(argumentCount) {
-		panic(fmt.Sprintf("Incorrect number of function arguments! Got %d, expected %d!", method.Type().NumIn(), int(argumentCount)))
-	}
+	name := fmt.Sprintf("%v.%s", data.typ, data.typ.Method(data.method).Name)
 
 	// Perform the call
-	ret := docall(ctx, method, argumentCount, arguments)
+	ret := docall(ctx, name, method, nil, function, thisObject, argumentCount, arguments)
+	if ret == nil {
+		return nil
+	}
 	return unsafe.Pointer(ret.ref)
 }
@@ -6,7 +6,6 @@ package gojs
 // #include "callback.h"
 import "C"
 import "unsafe"
-import "log"
 
 type Object struct {
 	ref C.JSObjectRef
@@ -190,7 +189,7 @@ func (obj *Object) GetProperty(name string) (*Value, error) {
 	return obj.ctx.newValue(ret), nil
 }
 
-func (obj *Object) GetPropertyAtIndex(index uint16) (*Value, error) {
+func (obj *Object) GetPropertyAtIndex(index uint32) (*Value, error) {
 	errVal := obj.ctx.newErrorValue()
 
 	ret := C.JSObjectGetPropertyAtIndex(obj.ctx.ref, obj.ref, C.unsigned(index), &errVal.ref)
@@ -216,7 +215,7 @@ func (obj *Object) SetProperty(name string, rhs *Value, attributes uint8) error
 	return nil
 }
 
-func (obj *Object) SetPropertyAtIndex(index uint16, rhs *Value) error {
+func (obj *Object) SetPropertyAtIndex(index uint32, rhs *Value) error {
 	errVal := obj.ctx.newErrorValue()
 
 	C.JSObjectSetPropertyAtIndex(obj.ctx.ref, obj.ref, C.unsigned(index), rhs.ref, &errVal.ref)
@@ -261,7 +260,6 @@ func (obj *Object) CallAsFunction(thisObject *Object, parameters []*Value) (*Val
 	cParameters, n := obj.ctx.newCValueArray(parameters)
 	if thisObject == nil {
 		thisObject = obj.ctx.newObject(nil)
-		log.Println(thisObject.ref)
 	}
 
 	ret := C.JSObjectCallAsFunction(obj.ctx.ref, obj.ref, thisObject.ref, n, cParameters, &errVal.ref)
@@ -329,12 +327,12 @@ func (ref *PropertyNameArray) Release() {
 	C.JSPropertyNameArrayRelease(C.JSPropertyNameArrayRef(unsafe.Pointer(ref)))
 }
 
-func (ref *PropertyNameArray) Count() uint16 {
+func (ref *PropertyNameArray) Count() int {
 	ret := C.JSPropertyNameArrayGetCount(C.JSPropertyNameArrayRef(unsafe.Pointer(ref)))
-	return uint16(ret)
+	return int(ret)
 }
 
-func (ref *PropertyNameArray) NameAtIndex(index uint16) string {
+func (ref *PropertyNameArray) NameAtIndex(index int) string {
 	jsstr := C.JSPropertyNameArrayGetNameAtIndex(C.JSPropertyNameArrayRef(unsafe.Pointer(ref)), C.size_t(index))
 	defer C.JSStringRelease(jsstr)
 	return (*String)(unsafe.Pointer(jsstr)).String()
@@ -0,0 +1,203 @@
+package gojs
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// NewOverloadedFunction creates a JavaScript function calling whichever of
+// the Go functions fns accepts the arguments of each call, like
+// format(date), format(date, layout) and format(number, precision).
+//
+// A function accepts a call if it takes as many arguments as are passed, or
+// more through a variadic or Rest parameter, and their types match its
+// parameters: strings match strings, numbers numeric types, Dates
+// time.Time, native objects the types they wrap, and so on, as described
+// for matchScore. Of the functions accepting a call, the one whose
+// parameters match most specifically is called, the first given on a tie.
+// If none accepts the call, functions that take more arguments than are
+// passed, or fewer, are considered in the same way, the missing arguments
+// being undefined as for NewFunctionWithNative. Calls that no function
+// accepts throw a TypeError listing the accepted signatures.
+//
+// Matching only looks at the arguments; they are converted for the
+// function called alone.
+//
+// Each function may take any of the parameters and return any of the
+// results that NewFunctionWithNative allows.
+func (ctx *Context) NewOverloadedFunction(fns ...interface{}) *Object {
+	vals := make([]reflect.Value, len(fns))
+	sigs := make([]signature, len(fns))
+	for i, fn := range fns {
+		vals[i] = reflect.ValueOf(fn)
+		if vals[i].Kind() != reflect.Func {
+			panic(fmt.Sprintf("Bad native function:  overload %d is not a function", i))
+		}
+		sigs[i] = signatureOf(vals[i].Type())
+	}
+
+	return ctx.NewFunctionWithCallback(func(ctx *Context, function, this *Object, args []*Value) *Value {
+		call := &CallInfo{Context: ctx, Function: function, This: this, Arguments: args}
+		if this.ref == nil {
+			call.This = nil
+		}
+		for _, exact := range []bool{true, false} {
+			best, bestScore := -1, -1
+			for i, sig := range sigs {
+				takesCount := len(args) == sig.n || (len(args) > sig.n && sig.takesSurplus())
+				if takesCount != exact || len(args) > sig.n && !sig.takesSurplus() && ctx.StrictArguments() {
+					continue
+				}
+				if score, ok := sig.match(args); ok && score > bestScore {
+					best, bestScore = i, score
+				}
+			}
+			if best < 0 {
+				continue
+			}
+			sig := sigs[best]
+			in, err := ctx.callArgs(funcName(vals[best]), sig.typ, call)
+			if err != nil {
+				panic(err)
+			}
+			ret, err := ctx.callResults(sig.typ, vals[best].Call(in), nil)
+			if err != nil {
+				panic(err)
+			}
+			return ret
+		}
+		panic(ctx.newTypeError(overloadError(sigs, args)))
+	})
+}
+
+// match reports whether args match the parameters of sig, with the sum of
+// their matchScores. Missing arguments are undefined, and surplus ones that
+// sig does not take are ignored.
+func (sig signature) match(args []*Value) (score int, ok bool) {
+	n := sig.n
+	if sig.typ.IsVariadic() {
+		n = max(n, len(args))
+	}
+	for i := 0; i < n; i++ {
+		var arg *Value
+		if i < len(args) {
+			arg = args[i]
+		}
+		s, ok := matchScore(arg, sig.paramType(i))
+		if !ok {
+			return 0, false
+		}
+		score += s
+	}
+	return score, true
+}
+
+// matchScore reports whether arg, nil for a missing argument, can convert to
+// typ as convertTo converts it, judging by its type alone, and how
+// specifically: from 1 for parameters taking any value, such as *Value and
+// interface{}, up to 4 for exact matches, such as strings for string
+// parameters. undefined and null match any type, but only score for types
+// that have nil values.
+func matchScore(arg *Value, typ reflect.Type) (int, bool) {
+	switch typ {
+	case valueType:
+		return 1, true
+	case objectType:
+		if arg == nil || arg.IsUndefined() || arg.IsNull() {
+			return 1, true
+		}
+		return 2, arg.IsObject()
+	}
+	if arg == nil || arg.IsUndefined() || arg.IsNull() {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Func, reflect.Map, reflect.Slice:
+			return 1, true
+		}
+		return 0, true
+	}
+	if arg.IsObject() {
+		if goObj, ok := arg.ToObjectOrDie().GoObject(); ok {
+			// As unwrapNative converts them.
+			t := reflect.TypeOf(goObj)
+			isPtr := t.Kind() == reflect.Ptr
+			switch {
+			case t == typ || isPtr && t.Elem() == typ:
+				return 4, true
+			case !t.AssignableTo(typ) && !(isPtr && t.Elem().AssignableTo(typ)):
+				return 0, false
+			case typ.NumMethod() > 0:
+				return 3, true
+			}
+			return 1, true
+		}
+	}
+
+	switch {
+	case typ == timeType:
+		return 4, arg.IsDate()
+	case typ == durationType && arg.IsNumber():
+		return 3, true
+	case typ == bigIntType:
+		if arg.IsBigInt() {
+			return 4, true
+		}
+		return 2, isIntegral(arg)
+	}
+	if want, ok := sliceTypedArrayType(typ); ok && arg.IsObject() {
+		if t := arg.TypedArrayType(); t != NotTypedArray {
+			return 4, t == want || want == Uint8Array && (t == Uint8ClampedArray || t == ArrayBuffer)
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return 4, arg.IsBoolean()
+	case reflect.String:
+		return 4, arg.IsString()
+	case reflect.Float32, reflect.Float64:
+		return 3, arg.IsNumber()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if arg.IsBigInt() {
+			return 2, true
+		}
+		return 3, isIntegral(arg)
+	case reflect.Interface:
+		return 1, typ.NumMethod() == 0
+	case reflect.Func:
+		return 4, arg.IsFunction()
+	case reflect.Ptr:
+		return matchScore(arg, typ.Elem())
+	case reflect.Slice, reflect.Array:
+		return 3, arg.IsArray()
+	case reflect.Map, reflect.Struct:
+		return 2, arg.IsObject() && !arg.IsArray() && !arg.IsFunction()
+	}
+	return 0, false
+}
+
+// isIntegral reports whether v is a number with an integral value.
+func isIntegral(v *Value) bool {
+	if !v.IsNumber() {
+		return false
+	}
+	n := v.ToNumberOrDie()
+	return n == math.Trunc(n) && !math.IsInf(n, 0)
+}
+
+// overloadError describes a call with args that no function of an
+// overloaded function accepts.
+func overloadError(sigs []signature, args []*Value) string {
+	kinds := make([]string, len(args))
+	for i, arg := range args {
+		kinds[i] = arg.Kind().String()
+	}
+	accepted := make([]string, len(sigs))
+	for i, sig := range sigs {
+		accepted[i] = sig.String()
+	}
+	return fmt.Sprintf("no overload accepts arguments (%s); accepted signatures are %s",
+		strings.Join(kinds, ", "), strings.Join(accepted, ", "))
+}
@@ -0,0 +1,64 @@
+package gojs
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewOverloadedFunction(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	format := ctx.NewOverloadedFunction(
+		func(t time.Time) string { return t.Format("2006-01-02") },
+		func(t time.Time, layout string) string { return t.Format(layout) },
+		func(x float64, precision int) string { return strconv.FormatFloat(x, 'f', precision, 64) },
+		func(this This, parts ...string) string { return strings.Join(parts, "/") },
+	)
+	ctx.GlobalObject().SetProperty("format", format.ToValue(), 0)
+
+	checkScript(t, ctx, "format(new Date(0))", "1970-01-01")
+	checkScript(t, ctx, "format(new Date(0), '2006')", "1970")
+	checkScript(t, ctx, "format(3.14159, 2)", "3.14")
+	checkScript(t, ctx, "format('a', 'b', 'c')", "a/b/c")
+	checkScript(t, ctx, "format(2.5)", "2")
+
+	val, err := ctx.EvaluateScript("format(true, {})", nil, "", 1)
+	if err == nil {
+		t.Fatalf("format(true, {}) = %v, want a TypeError", val)
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "TypeError") || !strings.Contains(msg, "(boolean, object)") ||
+		!strings.Contains(msg, "(time.Time, string)") || !strings.Contains(msg, "(...string)") {
+		t.Errorf("format(true, {}) threw %q, want a TypeError listing the signatures", msg)
+	}
+}
+
+func TestOverloadedFunctionSpecificity(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	describe := ctx.NewOverloadedFunction(
+		func(v interface{}) string { return "any" },
+		func(m map[string]interface{}) string { return "map" },
+		func(fn func() int) string { return "func " + strconv.Itoa(fn()) },
+		func(s string) string { return "string " + s },
+		func(n *int) string {
+			if n == nil {
+				return "nil"
+			}
+			return "int " + strconv.Itoa(*n)
+		},
+	)
+	ctx.GlobalObject().SetProperty("describe", describe.ToValue(), 0)
+
+	checkScript(t, ctx, "describe('x')", "string x")
+	checkScript(t, ctx, "describe(4)", "int 4")
+	checkScript(t, ctx, "describe(4.5)", "any")
+	checkScript(t, ctx, "describe(function () { return 1; })", "func 1")
+	checkScript(t, ctx, "describe({ a: 1 })", "map")
+	checkScript(t, ctx, "describe([1])", "any")
+	checkScript(t, ctx, "describe(undefined)", "any")
+}
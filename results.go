@@ -0,0 +1,57 @@
+package gojs
+
+import (
+	"reflect"
+)
+
+// resultTypes returns the result types of the native function type typ,
+// without a last error result.
+func resultTypes(typ reflect.Type) []reflect.Type {
+	n := typ.NumOut()
+	if n > 0 && typ.Out(n-1) == errorType {
+		n--
+	}
+	types := make([]reflect.Type, n)
+	for i := range types {
+		types[i] = typ.Out(i)
+	}
+	return types
+}
+
+// callResults converts the results out of a call to a native function of
+// type typ to JavaScript. A non-nil last error result is returned to be
+// thrown as an Error object instead. No results convert to undefined, one
+// result to its value, and more to an array of their values, or an object
+// with a property for each of names if given.
+func (ctx *Context) callResults(typ reflect.Type, out []reflect.Value, names []string) (*Value, error) {
+	if n := typ.NumOut(); n > 0 && typ.Out(n-1) == errorType {
+		if err, _ := out[n-1].Interface().(error); err != nil {
+			return nil, ctx.newException(err)
+		}
+		out = out[:n-1]
+	}
+
+	switch {
+	case len(out) == 0:
+		return nil, nil
+	case len(out) == 1 && names == nil:
+		return ctx.reflectToJSValue(out[0]), nil
+	case names != nil:
+		obj := ctx.NewEmptyObject()
+		for i, r := range out {
+			if err := obj.SetProperty(names[i], ctx.reflectToJSValue(r), 0); err != nil {
+				return nil, err
+			}
+		}
+		return obj.ToValue(), nil
+	}
+	items := make([]*Value, len(out))
+	for i, r := range out {
+		items[i] = ctx.reflectToJSValue(r)
+	}
+	arr, err := ctx.NewArray(items)
+	if err != nil {
+		return nil, err
+	}
+	return arr.ToValue(), nil
+}
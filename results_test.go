@@ -0,0 +1,50 @@
+package gojs
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestNativeFunctionResults(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	lookup := func(key string) (int, bool) {
+		n, ok := map[string]int{"a": 1}[key]
+		return n, ok
+	}
+	global := ctx.GlobalObject()
+	global.SetProperty("lookup", ctx.NewFunctionWithNative(lookup).ToValue(), 0)
+	global.SetProperty("find", ctx.NewFunctionWithNamedResults(lookup, "value", "ok").ToValue(), 0)
+	global.SetProperty("atoi", ctx.NewFunctionWithNative(strconv.Atoi).ToValue(), 0)
+	global.SetProperty("check", ctx.NewFunctionWithNative(func(ok bool) error {
+		if !ok {
+			return errors.New("check failed")
+		}
+		return nil
+	}).ToValue(), 0)
+	global.SetProperty("split", ctx.NewFunctionWithNamedResults(func(s string) (string, string, error) {
+		if len(s) < 2 {
+			return "", "", errors.New("too short")
+		}
+		return s[:1], s[1:], nil
+	}, "head", "tail").ToValue(), 0)
+
+	checkScript(t, ctx, "JSON.stringify(lookup('a'))", "[1,true]")
+	checkScript(t, ctx, "JSON.stringify(lookup('b'))", "[0,false]")
+	checkScript(t, ctx, "JSON.stringify(find('a'))", `{"value":1,"ok":true}`)
+	checkScript(t, ctx, "atoi('42')", "42")
+	checkScript(t, ctx, "try { atoi('x') } catch (e) { e instanceof Error && e.message.indexOf('invalid syntax') >= 0 }", "true")
+	checkScript(t, ctx, "check(true)", "undefined")
+	checkScript(t, ctx, "try { check(false) } catch (e) { e.message }", "check failed")
+	checkScript(t, ctx, "split('abc').tail", "bc")
+	checkScript(t, ctx, "try { split('a') } catch (e) { e.message }", "too short")
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("ctx.NewFunctionWithNamedResults did not panic for the wrong number of names")
+		}
+	}()
+	ctx.NewFunctionWithNamedResults(lookup, "value")
+}
@@ -0,0 +1,59 @@
+package gojs
+
+// Script is a script whose syntax has been checked and whose source has been
+// converted for JavaScriptCore once, so that it can be run repeatedly without
+// paying for either again.
+//
+// JavaScriptCore caches the code it compiles for a source in the context
+// group, so running a Script in any context of the same group also reuses
+// the compiled code.
+type Script struct {
+	ctx                *Context
+	source             *String
+	sourceURL          *String
+	startingLineNumber int
+}
+
+// Compile checks the syntax of script and returns a Script for it, or a
+// *SyntaxError. The returned Script must be released with Release once it is
+// no longer needed.
+func (ctx *Context) Compile(script string, sourceURL string, startingLineNumber int) (*Script, error) {
+	if err := ctx.CheckScriptSyntax(script, sourceURL, startingLineNumber); err != nil {
+		return nil, err
+	}
+
+	s := &Script{
+		ctx:                ctx,
+		source:             NewString(script),
+		startingLineNumber: startingLineNumber,
+	}
+	if sourceURL != "" {
+		s.sourceURL = NewString(sourceURL)
+	}
+	return s, nil
+}
+
+// Run evaluates the script in the context it was compiled in, like
+// EvaluateScript.
+func (s *Script) Run(thisObject *Object) (*Value, error) {
+	return s.RunIn(s.ctx, thisObject)
+}
+
+// RunIn evaluates the script in ctx. Contexts in the same group as the one
+// the script was compiled in share its compiled code.
+func (s *Script) RunIn(ctx *Context, thisObject *Object) (*Value, error) {
+	return ctx.evaluate(s.source, thisObject, s.sourceURL, s.startingLineNumber)
+}
+
+// Source returns the source code of the script.
+func (s *Script) Source() string {
+	return s.source.String()
+}
+
+// Release releases the source held by the script.
+func (s *Script) Release() {
+	s.source.Release()
+	if s.sourceURL != nil {
+		s.sourceURL.Release()
+	}
+}
@@ -0,0 +1,68 @@
+package gojs
+
+import (
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	for index, item := range basetests {
+		script, err := ctx.Compile(item.script, "./testing.go", 1)
+		if item.result == "" {
+			if err == nil {
+				t.Errorf("ctx.Compile did not raise an error on an invalid script (script %v)", index)
+				script.Release()
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ctx.Compile raised an error (script %v): %v", index, err)
+			continue
+		}
+		if script.Source() != item.script {
+			t.Errorf("script.Source() returned %q, want %q", script.Source(), item.script)
+		}
+
+		// Repeated runs give the same result.
+		for i := 0; i < 3; i++ {
+			ret, err := script.Run(nil)
+			if err != nil {
+				t.Errorf("script.Run raised an error (script %v): %v", index, err)
+			} else if ret.ToStringOrDie() != item.result {
+				t.Errorf("script.Run returned %v, want %v", ret.ToStringOrDie(), item.result)
+			}
+		}
+		script.Release()
+	}
+}
+
+func TestScriptRunIn(t *testing.T) {
+	group := NewContextGroup()
+	defer group.Release()
+
+	ctx1 := group.NewContext()
+	defer ctx1.Release()
+	ctx2 := group.NewContext()
+	defer ctx2.Release()
+
+	script, err := ctx1.Compile("var counter = (typeof counter == 'number' ? counter : 0) + 1; counter", "", 1)
+	if err != nil {
+		t.Fatalf("ctx.Compile failed: %v", err)
+	}
+	defer script.Release()
+
+	for _, want := range []float64{1, 2} {
+		ret, err := script.Run(nil)
+		if err != nil || ret.ToNumberOrDie() != want {
+			t.Errorf("script.Run returned %v (%v), want %v", ret, err, want)
+		}
+	}
+
+	// Each context keeps its own globals.
+	ret, err := script.RunIn(ctx2, nil)
+	if err != nil || ret.ToNumberOrDie() != 1 {
+		t.Errorf("script.RunIn returned %v (%v), want 1", ret, err)
+	}
+}
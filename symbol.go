@@ -0,0 +1,172 @@
+package gojs
+
+// #include <JavaScriptCore/JSStringRef.h>
+// #include "compat.h"
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// WellKnownSymbol names one of the symbols that are properties of the Symbol
+// function, such as Symbol.iterator.
+type WellKnownSymbol string
+
+const (
+	SymbolAsyncIterator      WellKnownSymbol = "asyncIterator"
+	SymbolHasInstance        WellKnownSymbol = "hasInstance"
+	SymbolIsConcatSpreadable WellKnownSymbol = "isConcatSpreadable"
+	SymbolIterator           WellKnownSymbol = "iterator"
+	SymbolMatch              WellKnownSymbol = "match"
+	SymbolMatchAll           WellKnownSymbol = "matchAll"
+	SymbolReplace            WellKnownSymbol = "replace"
+	SymbolSearch             WellKnownSymbol = "search"
+	SymbolSpecies            WellKnownSymbol = "species"
+	SymbolSplit              WellKnownSymbol = "split"
+	SymbolToPrimitive        WellKnownSymbol = "toPrimitive"
+	SymbolToStringTag        WellKnownSymbol = "toStringTag"
+	SymbolUnscopables        WellKnownSymbol = "unscopables"
+)
+
+// newSymbolSource evaluates to the Symbol function, for JavaScriptCores that
+// cannot create symbols through the C API.
+const newSymbolSource = `(function (description) {
+	if (typeof Symbol !== "function") {
+		throw new TypeError("Symbol is not supported by this JavaScriptCore");
+	}
+	return Symbol(description);
+})`
+
+// NewSymbol creates a new symbol with description, like Symbol(description).
+func (ctx *Context) NewSymbol(description string) (*Value, error) {
+	if C.gojs_symbols() == 0 {
+		return ctx.callHelper(newSymbolSource, ctx.NewStringValue(description))
+	}
+	jsstr := NewString(description)
+	defer jsstr.Release()
+	return ctx.newValue(C.gojs_make_symbol(ctx.ref, C.JSStringRef(unsafe.Pointer(jsstr)))), nil
+}
+
+// Symbol returns the well-known symbol s, such as Symbol.iterator.
+func (ctx *Context) Symbol(s WellKnownSymbol) (*Value, error) {
+	symbol, err := ctx.GlobalObject().GetProperty("Symbol")
+	if err != nil {
+		return nil, err
+	}
+	if !symbol.IsObject() {
+		return nil, fmt.Errorf("gojs: Symbol is not supported by this JavaScriptCore")
+	}
+	ret, err := symbol.ToObjectOrDie().GetProperty(string(s))
+	if err != nil {
+		return nil, err
+	}
+	if !ret.IsSymbol() {
+		return nil, fmt.Errorf("gojs: Symbol.%s is not supported by this JavaScriptCore", s)
+	}
+	return ret, nil
+}
+
+// propertyForKeySource evaluates to a function implementing the property
+// functions taking keys as values, for JavaScriptCores whose C API does not
+// have them.
+const propertyForKeySource = `(function (op, obj, key, value, attributes) {
+	switch (op) {
+	case "has":
+		return key in obj;
+	case "get":
+		return obj[key];
+	case "set":
+		if (attributes === 0) {
+			obj[key] = value;
+			return;
+		}
+		Object.defineProperty(obj, key, {
+			value: value,
+			writable: (attributes & 2) === 0,
+			enumerable: (attributes & 4) === 0,
+			configurable: (attributes & 8) === 0
+		});
+		return;
+	case "delete":
+		return delete obj[key];
+	}
+})`
+
+func (obj *Object) propertyForKey(op string, key, value *Value, attributes uint8) (*Value, error) {
+	if value == nil {
+		value = obj.ctx.NewUndefinedValue()
+	}
+	return obj.ctx.callHelper(propertyForKeySource, obj.ctx.NewStringValue(op), obj.ToValue(),
+		key, value, obj.ctx.NewNumberValue(float64(attributes)))
+}
+
+// HasPropertyForKey reports whether obj has a property, maybe inherited,
+// whose key is key, like the in operator. Keys that are not symbols are
+// converted to strings.
+func (obj *Object) HasPropertyForKey(key *Value) (bool, error) {
+	if C.gojs_symbols() == 0 {
+		ret, err := obj.propertyForKey("has", key, nil, 0)
+		if err != nil {
+			return false, err
+		}
+		return ret.ToBoolean(), nil
+	}
+
+	errVal := obj.ctx.newErrorValue()
+	ret := C.gojs_has_property_for_key(obj.ctx.ref, obj.ref, key.ref, &errVal.ref)
+	if errVal.ref != nil {
+		return false, errVal
+	}
+	return bool(ret), nil
+}
+
+// GetPropertyForKey is like GetProperty, for a property whose key is key,
+// which may be a symbol.
+func (obj *Object) GetPropertyForKey(key *Value) (*Value, error) {
+	if C.gojs_symbols() == 0 {
+		return obj.propertyForKey("get", key, nil, 0)
+	}
+
+	errVal := obj.ctx.newErrorValue()
+	ret := C.gojs_get_property_for_key(obj.ctx.ref, obj.ref, key.ref, &errVal.ref)
+	if errVal.ref != nil {
+		return nil, errVal
+	}
+	return obj.ctx.newValue(ret), nil
+}
+
+// SetPropertyForKey is like SetProperty, for a property whose key is key,
+// which may be a symbol.
+func (obj *Object) SetPropertyForKey(key *Value, rhs *Value, attributes uint8) error {
+	if C.gojs_symbols() == 0 {
+		_, err := obj.propertyForKey("set", key, rhs, attributes)
+		return err
+	}
+
+	errVal := obj.ctx.newErrorValue()
+	C.gojs_set_property_for_key(obj.ctx.ref, obj.ref, key.ref, rhs.ref,
+		(C.JSPropertyAttributes)(attributes), &errVal.ref)
+	if errVal.ref != nil {
+		return errVal
+	}
+	return nil
+}
+
+// DeletePropertyForKey is like DeleteProperty, for a property whose key is
+// key, which may be a symbol.
+func (obj *Object) DeletePropertyForKey(key *Value) (bool, error) {
+	if C.gojs_symbols() == 0 {
+		ret, err := obj.propertyForKey("delete", key, nil, 0)
+		if err != nil {
+			return false, err
+		}
+		return ret.ToBoolean(), nil
+	}
+
+	errVal := obj.ctx.newErrorValue()
+	ret := C.gojs_delete_property_for_key(obj.ctx.ref, obj.ref, key.ref, &errVal.ref)
+	if errVal.ref != nil {
+		return false, errVal
+	}
+	return bool(ret), nil
+}
@@ -0,0 +1,83 @@
+package gojs
+
+import (
+	"testing"
+)
+
+func TestNewSymbol(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	sym, err := ctx.NewSymbol("tag")
+	if err != nil {
+		t.Fatalf("ctx.NewSymbol failed: %v", err)
+	}
+	if !sym.IsSymbol() {
+		t.Fatalf("ctx.NewSymbol returned a %v", sym.Kind())
+	}
+	ctx.GlobalObject().SetProperty("sym", sym, 0)
+	checkScript(t, ctx, "sym.description", "tag")
+
+	other, _ := ctx.NewSymbol("tag")
+	if sym.Equals(other) {
+		t.Errorf("two symbols from ctx.NewSymbol are equal")
+	}
+}
+
+func TestWellKnownSymbol(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	iterator, err := ctx.Symbol(SymbolIterator)
+	if err != nil {
+		t.Fatalf("ctx.Symbol(SymbolIterator) failed: %v", err)
+	}
+	want, _ := ctx.EvaluateScript("Symbol.iterator", nil, "", 1)
+	if !iterator.Equals(want) {
+		t.Errorf("ctx.Symbol(SymbolIterator) is not Symbol.iterator")
+	}
+	if _, err := ctx.Symbol("noSuchSymbol"); err == nil {
+		t.Errorf("ctx.Symbol of an unknown name succeeded")
+	}
+}
+
+func TestPropertyForKey(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	obj := ctx.NewEmptyObject()
+	toPrimitive, err := ctx.Symbol(SymbolToPrimitive)
+	if err != nil {
+		t.Fatalf("ctx.Symbol(SymbolToPrimitive) failed: %v", err)
+	}
+	fn := ctx.NewFunctionWithCallback(func(ctx *Context, _, _ *Object, _ []*Value) *Value {
+		return ctx.NewNumberValue(42)
+	})
+	if err := obj.SetPropertyForKey(toPrimitive, fn.ToValue(), PropertyAttributeDontEnum); err != nil {
+		t.Fatalf("obj.SetPropertyForKey failed: %v", err)
+	}
+	ctx.GlobalObject().SetProperty("obj", obj.ToValue(), 0)
+	checkScript(t, ctx, "obj + 1", "43")
+	checkScript(t, ctx, "Object.keys(obj).length", "0")
+
+	if has, err := obj.HasPropertyForKey(toPrimitive); err != nil || !has {
+		t.Errorf("obj.HasPropertyForKey(Symbol.toPrimitive) = %v, %v, want true", has, err)
+	}
+	if v, err := obj.GetPropertyForKey(toPrimitive); err != nil || !v.Equals(fn.ToValue()) {
+		t.Errorf("obj.GetPropertyForKey(Symbol.toPrimitive) = %v, %v, want the function", v, err)
+	}
+	if deleted, err := obj.DeletePropertyForKey(toPrimitive); err != nil || !deleted {
+		t.Errorf("obj.DeletePropertyForKey(Symbol.toPrimitive) = %v, %v, want true", deleted, err)
+	}
+	if has, _ := obj.HasPropertyForKey(toPrimitive); has {
+		t.Errorf("obj still has Symbol.toPrimitive after deleting it")
+	}
+
+	// Other keys are converted to strings.
+	if err := obj.SetPropertyForKey(ctx.NewNumberValue(1), ctx.NewStringValue("one"), 0); err != nil {
+		t.Fatalf("obj.SetPropertyForKey(1) failed: %v", err)
+	}
+	if v, err := obj.GetProperty("1"); err != nil || v.String() != "one" {
+		t.Errorf("obj.GetProperty(\"1\") = %v, %v, want one", v, err)
+	}
+}
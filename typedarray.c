@@ -0,0 +1,113 @@
+#include <stdlib.h>
+#include "_cgo_export.h"
+#include "typedarray.h"
+
+#ifdef GOJS_HAVE_TYPED_ARRAYS
+
+static void free_bytes(void* bytes, void* deallocatorContext)
+{
+	free( bytes );
+}
+
+static void release_bytes(void* bytes, void* deallocatorContext)
+{
+	release_bytes_go( (uintptr_t)deallocatorContext );
+}
+
+int gojs_typed_arrays()
+{
+	return 1;
+}
+
+int gojs_typed_array_kind(JSContextRef ctx, JSValueRef value, JSValueRef* exception)
+{
+	return JSValueGetTypedArrayType( ctx, value, exception );
+}
+
+JSObjectRef gojs_make_typed_array(JSContextRef ctx, int kind, size_t length, JSValueRef* exception)
+{
+	return JSObjectMakeTypedArray( ctx, (JSTypedArrayType)kind, length, exception );
+}
+
+JSObjectRef gojs_make_typed_array_with_buffer(JSContextRef ctx, int kind, JSObjectRef buffer, size_t byteOffset, size_t length, JSValueRef* exception)
+{
+	return JSObjectMakeTypedArrayWithArrayBufferAndOffset( ctx, (JSTypedArrayType)kind, buffer, byteOffset, length, exception );
+}
+
+JSObjectRef gojs_make_array_buffer_malloced(JSContextRef ctx, void* bytes, size_t byteLength, JSValueRef* exception)
+{
+	return JSObjectMakeArrayBufferWithBytesNoCopy( ctx, bytes, byteLength, free_bytes, NULL, exception );
+}
+
+JSObjectRef gojs_make_array_buffer_released(JSContextRef ctx, void* bytes, size_t byteLength, uintptr_t release, JSValueRef* exception)
+{
+	return JSObjectMakeArrayBufferWithBytesNoCopy( ctx, bytes, byteLength, release_bytes, (void*)release, exception );
+}
+
+void* gojs_bytes(JSContextRef ctx, JSObjectRef object, int kind, size_t* byteLength, JSValueRef* exception)
+{
+	char* bytes;
+
+	if ( kind == kJSTypedArrayTypeArrayBuffer ) {
+		*byteLength = JSObjectGetArrayBufferByteLength( ctx, object, exception );
+		if ( *exception ) {
+			return NULL;
+		}
+		return JSObjectGetArrayBufferBytesPtr( ctx, object, exception );
+	}
+
+	// The bytes pointer of a typed array is that of its whole buffer.
+	*byteLength = JSObjectGetTypedArrayByteLength( ctx, object, exception );
+	if ( *exception ) {
+		return NULL;
+	}
+	size_t offset = JSObjectGetTypedArrayByteOffset( ctx, object, exception );
+	if ( *exception ) {
+		return NULL;
+	}
+	bytes = JSObjectGetTypedArrayBytesPtr( ctx, object, exception );
+	if ( *exception || !bytes ) {
+		return NULL;
+	}
+	return bytes + offset;
+}
+
+#else
+
+int gojs_typed_arrays()
+{
+	return 0;
+}
+
+int gojs_typed_array_kind(JSContextRef ctx, JSValueRef value, JSValueRef* exception)
+{
+	return GOJS_TYPED_ARRAY_NONE;
+}
+
+JSObjectRef gojs_make_typed_array(JSContextRef ctx, int kind, size_t length, JSValueRef* exception)
+{
+	return NULL;
+}
+
+JSObjectRef gojs_make_typed_array_with_buffer(JSContextRef ctx, int kind, JSObjectRef buffer, size_t byteOffset, size_t length, JSValueRef* exception)
+{
+	return NULL;
+}
+
+JSObjectRef gojs_make_array_buffer_malloced(JSContextRef ctx, void* bytes, size_t byteLength, JSValueRef* exception)
+{
+	return NULL;
+}
+
+JSObjectRef gojs_make_array_buffer_released(JSContextRef ctx, void* bytes, size_t byteLength, uintptr_t release, JSValueRef* exception)
+{
+	return NULL;
+}
+
+void* gojs_bytes(JSContextRef ctx, JSObjectRef object, int kind, size_t* byteLength, JSValueRef* exception)
+{
+	*byteLength = 0;
+	return NULL;
+}
+
+#endif
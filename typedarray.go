@@ -0,0 +1,303 @@
+package gojs
+
+// #include <stdlib.h>
+// #include "typedarray.h"
+import "C"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// TypedArrayType is the type of a typed array such as a Uint8Array, or
+// ArrayBuffer for ArrayBuffers.
+type TypedArrayType int
+
+const (
+	Int8Array TypedArrayType = iota
+	Int16Array
+	Int32Array
+	Uint8Array
+	Uint8ClampedArray
+	Uint16Array
+	Uint32Array
+	Float32Array
+	Float64Array
+	ArrayBuffer
+	// NotTypedArray is the type of values that are neither typed arrays nor
+	// ArrayBuffers.
+	NotTypedArray
+	BigInt64Array
+	BigUint64Array
+)
+
+var typedArrayNames = [...]string{
+	"Int8Array",
+	"Int16Array",
+	"Int32Array",
+	"Uint8Array",
+	"Uint8ClampedArray",
+	"Uint16Array",
+	"Uint32Array",
+	"Float32Array",
+	"Float64Array",
+	"ArrayBuffer",
+	"NotTypedArray",
+	"BigInt64Array",
+	"BigUint64Array",
+}
+
+func (t TypedArrayType) String() string {
+	if t < 0 || int(t) >= len(typedArrayNames) {
+		return fmt.Sprintf("TypedArrayType(%d)", int(t))
+	}
+	return typedArrayNames[t]
+}
+
+// ElementSize returns the size in bytes of the elements of typed arrays of
+// type t, or 0 if t is not a typed array type.
+func (t TypedArrayType) ElementSize() int {
+	switch t {
+	case Int8Array, Uint8Array, Uint8ClampedArray:
+		return 1
+	case Int16Array, Uint16Array:
+		return 2
+	case Int32Array, Uint32Array, Float32Array:
+		return 4
+	case Float64Array, BigInt64Array, BigUint64Array:
+		return 8
+	}
+	return 0
+}
+
+// ErrTypedArraysUnsupported is returned when creating typed arrays or
+// ArrayBuffers with a JavaScriptCore that has no typed array API, such as
+// that of the 3.0 API.
+var ErrTypedArraysUnsupported = errors.New("gojs: typed arrays are not supported by this JavaScriptCore")
+
+// NewArrayBuffer creates an ArrayBuffer holding a copy of data.
+func (ctx *Context) NewArrayBuffer(data []byte) (*Object, error) {
+	if C.gojs_typed_arrays() == 0 {
+		return nil, ErrTypedArraysUnsupported
+	}
+	// The buffer frees bytes when it is collected, or if creating it fails.
+	bytes := C.malloc(C.size_t(max(len(data), 1)))
+	copy(unsafe.Slice((*byte)(bytes), len(data)), data)
+
+	errVal := ctx.newErrorValue()
+	ret := C.gojs_make_array_buffer_malloced(ctx.ref, bytes, C.size_t(len(data)), &errVal.ref)
+	if errVal.ref != nil {
+		return nil, errVal
+	}
+	return ctx.newObject(ret), nil
+}
+
+// NewArrayBufferNoCopy creates an ArrayBuffer whose storage is data itself,
+// without copying it. Writes by the script are visible in data and the
+// other way round. data is pinned so that the garbage collector does not
+// move it until the buffer is collected; then release, if not nil, is
+// called. Do not use data from another goroutine while a script runs.
+func (ctx *Context) NewArrayBufferNoCopy(data []byte, release func()) (*Object, error) {
+	var pinner runtime.Pinner
+	var bytes unsafe.Pointer
+	if len(data) > 0 {
+		pinner.Pin(&data[0])
+		bytes = unsafe.Pointer(&data[0])
+	}
+	obj, err := ctx.newArrayBufferReleased(bytes, len(data), func() {
+		pinner.Unpin()
+		if release != nil {
+			release()
+		}
+	})
+	if err == ErrTypedArraysUnsupported {
+		pinner.Unpin()
+	}
+	return obj, err
+}
+
+// NewArrayBufferFromC creates an ArrayBuffer over length bytes of memory
+// allocated outside Go, at ptr, without copying it. dealloc, if not nil, is
+// called with ptr once the buffer is collected, to free the memory.
+func (ctx *Context) NewArrayBufferFromC(ptr unsafe.Pointer, length int, dealloc func(ptr unsafe.Pointer)) (*Object, error) {
+	return ctx.newArrayBufferReleased(ptr, length, func() {
+		if dealloc != nil {
+			dealloc(ptr)
+		}
+	})
+}
+
+// newArrayBufferReleased creates an ArrayBuffer over bytes, calling release
+// once JavaScriptCore no longer uses them.
+func (ctx *Context) newArrayBufferReleased(bytes unsafe.Pointer, length int, release func()) (*Object, error) {
+	if C.gojs_typed_arrays() == 0 {
+		return nil, ErrTypedArraysUnsupported
+	}
+	handle := cgo.NewHandle(release)
+
+	// The buffer calls release when it is collected, or if creating it fails.
+	errVal := ctx.newErrorValue()
+	ret := C.gojs_make_array_buffer_released(ctx.ref, bytes, C.size_t(length), C.uintptr_t(handle), &errVal.ref)
+	if errVal.ref != nil {
+		return nil, errVal
+	}
+	return ctx.newObject(ret), nil
+}
+
+//export release_bytes_go
+func release_bytes_go(handle C.uintptr_t) {
+	// Called from JavaScriptCore when an ArrayBuffer is collected
+	h := cgo.Handle(handle)
+	release := h.Value().(func())
+	h.Delete()
+	release()
+}
+
+// NewTypedArray creates a typed array of type t holding a copy of data,
+// which holds the elements in the byte order of the machine. The length of
+// data must be a multiple of the element size of t.
+func (ctx *Context) NewTypedArray(t TypedArrayType, data []byte) (*Object, error) {
+	size := t.ElementSize()
+	if size == 0 {
+		return nil, fmt.Errorf("gojs: %v is not a typed array type", t)
+	}
+	if len(data)%size != 0 {
+		return nil, fmt.Errorf("gojs: %d bytes do not hold a whole number of %v elements", len(data), t)
+	}
+	if C.gojs_typed_arrays() == 0 {
+		return nil, ErrTypedArraysUnsupported
+	}
+
+	errVal := ctx.newErrorValue()
+	ret := C.gojs_make_typed_array(ctx.ref, C.int(t), C.size_t(len(data)/size), &errVal.ref)
+	if errVal.ref != nil {
+		return nil, errVal
+	}
+	obj := ctx.newObject(ret)
+	if len(data) > 0 {
+		bytes, err := obj.ToValue().Bytes()
+		if err != nil {
+			return nil, err
+		}
+		copy(bytes, data)
+	}
+	return obj, nil
+}
+
+// NewTypedArrayWithBuffer creates a typed array of type t viewing length
+// elements of the ArrayBuffer buffer, starting byteOffset bytes into it.
+// Together with NewArrayBufferNoCopy, it gives scripts typed arrays over Go
+// memory.
+func (ctx *Context) NewTypedArrayWithBuffer(t TypedArrayType, buffer *Object, byteOffset, length int) (*Object, error) {
+	if t.ElementSize() == 0 {
+		return nil, fmt.Errorf("gojs: %v is not a typed array type", t)
+	}
+	if byteOffset < 0 || length < 0 {
+		return nil, ErrIndexOutOfRange
+	}
+	if C.gojs_typed_arrays() == 0 {
+		return nil, ErrTypedArraysUnsupported
+	}
+
+	errVal := ctx.newErrorValue()
+	ret := C.gojs_make_typed_array_with_buffer(ctx.ref, C.int(t), buffer.ref, C.size_t(byteOffset), C.size_t(length), &errVal.ref)
+	if errVal.ref != nil {
+		return nil, errVal
+	}
+	return ctx.newObject(ret), nil
+}
+
+// TypedArrayType returns the type of the typed array or ArrayBuffer v, or
+// NotTypedArray for other values.
+func (v *Value) TypedArrayType() TypedArrayType {
+	errVal := v.ctx.newErrorValue()
+	t := C.gojs_typed_array_kind(v.ctx.ref, v.ref, &errVal.ref)
+	if errVal.ref != nil {
+		return NotTypedArray
+	}
+	return TypedArrayType(t)
+}
+
+// Bytes returns the storage of the ArrayBuffer or typed array v, such as a
+// Uint8Array, without copying it. For typed arrays, it is the part of their
+// buffer that they view. Writes to the slice are visible to scripts and the
+// other way round. The slice is only valid while v is reachable, so keep v
+// protected or referenced from the script while using it.
+func (v *Value) Bytes() ([]byte, error) {
+	t := v.TypedArrayType()
+	if t == NotTypedArray {
+		return nil, fmt.Errorf("gojs: %v is not a typed array or ArrayBuffer", v)
+	}
+	obj, err := v.ToObject()
+	if err != nil {
+		return nil, err
+	}
+
+	var length C.size_t
+	errVal := v.ctx.newErrorValue()
+	bytes := C.gojs_bytes(v.ctx.ref, obj.ref, C.int(t), &length, &errVal.ref)
+	if errVal.ref != nil {
+		return nil, errVal
+	}
+	if bytes == nil || length == 0 {
+		return []byte{}, nil
+	}
+	return unsafe.Slice((*byte)(bytes), int(length)), nil
+}
+
+// sliceTypedArrayType returns the type of typed array that slices of type t
+// convert to and from, if any.
+func sliceTypedArrayType(t reflect.Type) (TypedArrayType, bool) {
+	if t.Kind() != reflect.Slice {
+		return NotTypedArray, false
+	}
+	switch t.Elem().Kind() {
+	case reflect.Int8:
+		return Int8Array, true
+	case reflect.Int16:
+		return Int16Array, true
+	case reflect.Int32:
+		return Int32Array, true
+	case reflect.Uint8:
+		return Uint8Array, true
+	case reflect.Uint16:
+		return Uint16Array, true
+	case reflect.Uint32:
+		return Uint32Array, true
+	case reflect.Float32:
+		return Float32Array, true
+	case reflect.Float64:
+		return Float64Array, true
+	}
+	return NotTypedArray, false
+}
+
+// newTypedArrayFromSlice converts a slice of one of the types accepted by
+// sliceTypedArrayType into a typed array holding a copy of its elements.
+func (ctx *Context) newTypedArrayFromSlice(slice reflect.Value) (*Object, error) {
+	t, _ := sliceTypedArrayType(slice.Type())
+	data := unsafe.Slice((*byte)(slice.UnsafePointer()), slice.Len()*t.ElementSize())
+	return ctx.NewTypedArray(t, data)
+}
+
+// typedArrayToSlice copies the elements of the typed array v into a new
+// slice of type typ, which must be accepted by sliceTypedArrayType. Byte
+// slices also accept Uint8ClampedArrays and ArrayBuffers.
+func typedArrayToSlice(v *Value, typ reflect.Type) (reflect.Value, error) {
+	want, _ := sliceTypedArrayType(typ)
+	t := v.TypedArrayType()
+	if t != want && !(want == Uint8Array && (t == Uint8ClampedArray || t == ArrayBuffer)) {
+		return reflect.Value{}, fmt.Errorf("gojs: cannot convert %v to %v", t, typ)
+	}
+	bytes, err := v.Bytes()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	n := len(bytes) / want.ElementSize()
+	slice := reflect.MakeSlice(typ, n, n)
+	copy(unsafe.Slice((*byte)(slice.UnsafePointer()), len(bytes)), bytes)
+	return slice, nil
+}
@@ -0,0 +1,17 @@
+#include <stdint.h>
+#include "compat.h"
+
+// Without GOJS_HAVE_TYPED_ARRAYS, the functions below fail and
+// gojs_typed_arrays returns 0.
+
+// GOJS_TYPED_ARRAY_NONE is the kind of values that are neither typed arrays
+// nor ArrayBuffers. Other kinds are JSTypedArrayType values.
+#define GOJS_TYPED_ARRAY_NONE 10
+
+int gojs_typed_arrays();
+int gojs_typed_array_kind(JSContextRef ctx, JSValueRef value, JSValueRef* exception);
+JSObjectRef gojs_make_typed_array(JSContextRef ctx, int kind, size_t length, JSValueRef* exception);
+JSObjectRef gojs_make_typed_array_with_buffer(JSContextRef ctx, int kind, JSObjectRef buffer, size_t byteOffset, size_t length, JSValueRef* exception);
+JSObjectRef gojs_make_array_buffer_malloced(JSContextRef ctx, void* bytes, size_t byteLength, JSValueRef* exception);
+JSObjectRef gojs_make_array_buffer_released(JSContextRef ctx, void* bytes, size_t byteLength, uintptr_t release, JSValueRef* exception);
+void* gojs_bytes(JSContextRef ctx, JSObjectRef object, int kind, size_t* byteLength, JSValueRef* exception);
@@ -0,0 +1,255 @@
+package gojs
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+	"unsafe"
+)
+
+func TestNewArrayBuffer(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	data := []byte{1, 2, 3, 4}
+	buf, err := ctx.NewArrayBuffer(data)
+	if err != nil {
+		t.Fatalf("ctx.NewArrayBuffer failed: %v", err)
+	}
+	if typ := buf.ToValue().TypedArrayType(); typ != ArrayBuffer {
+		t.Errorf("buf.TypedArrayType() = %v, want ArrayBuffer", typ)
+	}
+
+	// The buffer holds a copy of data.
+	data[0] = 9
+	b, err := buf.ToValue().Bytes()
+	if err != nil {
+		t.Fatalf("buf.Bytes failed: %v", err)
+	}
+	if !bytes.Equal(b, []byte{1, 2, 3, 4}) {
+		t.Errorf("buf.Bytes() = %v, want [1 2 3 4]", b)
+	}
+
+	ctx.GlobalObject().SetProperty("buf", buf.ToValue(), 0)
+	val, err := ctx.EvaluateScript("var a = new Uint8Array(buf); a[1] = 7; a.byteLength", nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	if n := val.ToNumberOrDie(); n != 4 {
+		t.Errorf("byteLength = %v, want 4", n)
+	}
+	if b[1] != 7 {
+		t.Errorf("Bytes did not see a write from the script: %v", b)
+	}
+}
+
+func TestNewArrayBufferNoCopy(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	data := []byte("hello")
+	released := make(chan bool, 1)
+	buf, err := ctx.NewArrayBufferNoCopy(data, func() { released <- true })
+	if err != nil {
+		t.Fatalf("ctx.NewArrayBufferNoCopy failed: %v", err)
+	}
+
+	ctx.GlobalObject().SetProperty("buf", buf.ToValue(), 0)
+	if _, err := ctx.EvaluateScript("new Uint8Array(buf)[0] = 72; buf = null", nil, "", 1); err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	if string(data) != "Hello" {
+		t.Errorf("data = %q after the script wrote to it, want %q", data, "Hello")
+	}
+
+	ctx.GarbageCollect()
+	select {
+	case <-released:
+	default:
+		t.Log("the buffer was not released by the first collection")
+	}
+}
+
+func TestNewArrayBufferFromC(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	// Tests cannot use cgo, so pinned Go memory stands in for C memory.
+	mem := make([]byte, 8)
+	var pinner runtime.Pinner
+	pinner.Pin(&mem[0])
+	defer pinner.Unpin()
+
+	buf, err := ctx.NewArrayBufferFromC(unsafe.Pointer(&mem[0]), len(mem), nil)
+	if err != nil {
+		t.Fatalf("ctx.NewArrayBufferFromC failed: %v", err)
+	}
+	b, err := buf.ToValue().Bytes()
+	if err != nil {
+		t.Fatalf("buf.Bytes failed: %v", err)
+	}
+	if len(b) != 8 || &b[0] != &mem[0] {
+		t.Errorf("buf.Bytes() does not view the memory the buffer was created over")
+	}
+}
+
+func TestNewTypedArray(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	floats := []float32{1.5, -2, 4}
+	data := unsafe.Slice((*byte)(unsafe.Pointer(&floats[0])), 12)
+	arr, err := ctx.NewTypedArray(Float32Array, data)
+	if err != nil {
+		t.Fatalf("ctx.NewTypedArray failed: %v", err)
+	}
+	ctx.GlobalObject().SetProperty("arr", arr.ToValue(), 0)
+	checkScript(t, ctx, "arr instanceof Float32Array && arr.length", "3")
+	checkScript(t, ctx, "Array.prototype.join.call(arr, ',')", "1.5,-2,4")
+
+	if _, err := ctx.NewTypedArray(Int32Array, []byte{1, 2, 3}); err == nil {
+		t.Errorf("ctx.NewTypedArray succeeded with a partial element")
+	}
+	if _, err := ctx.NewTypedArray(ArrayBuffer, nil); err == nil {
+		t.Errorf("ctx.NewTypedArray succeeded for ArrayBuffer")
+	}
+
+	buf, err := ctx.NewArrayBuffer([]byte{0, 1, 2, 3, 4, 5, 6, 7})
+	if err != nil {
+		t.Fatalf("ctx.NewArrayBuffer failed: %v", err)
+	}
+	view, err := ctx.NewTypedArrayWithBuffer(Uint8Array, buf, 2, 4)
+	if err != nil {
+		t.Fatalf("ctx.NewTypedArrayWithBuffer failed: %v", err)
+	}
+	b, err := view.ToValue().Bytes()
+	if err != nil {
+		t.Fatalf("view.Bytes failed: %v", err)
+	}
+	if !bytes.Equal(b, []byte{2, 3, 4, 5}) {
+		t.Errorf("view.Bytes() = %v, want [2 3 4 5]", b)
+	}
+}
+
+func TestValue_Bytes(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	val, err := ctx.EvaluateScript("new Uint8Array([10, 20, 30]).subarray(1)", nil, "", 1)
+	if err != nil {
+		t.Fatalf("ctx.EvaluateScript failed: %v", err)
+	}
+	b, err := val.Bytes()
+	if err != nil {
+		t.Fatalf("val.Bytes failed: %v", err)
+	}
+	if !bytes.Equal(b, []byte{20, 30}) {
+		t.Errorf("val.Bytes() = %v, want [20 30]", b)
+	}
+
+	if _, err := ctx.NewStringValue("abc").Bytes(); err == nil {
+		t.Errorf("Bytes succeeded for a string")
+	}
+	if typ := ctx.NewNumberValue(1).TypedArrayType(); typ != NotTypedArray {
+		t.Errorf("TypedArrayType() of a number = %v, want NotTypedArray", typ)
+	}
+}
+
+func TestReflectTypedArrays(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	tests := []struct {
+		value  interface{}
+		script string
+	}{
+		{[]byte{1, 2}, "v instanceof Uint8Array && v.join()"},
+		{[]int8{-1, 2}, "v instanceof Int8Array && v.join()"},
+		{[]int32{-1, 2}, "v instanceof Int32Array && v.join()"},
+		{[]uint16{1, 2}, "v instanceof Uint16Array && v.join()"},
+		{[]float32{-1, 2}, "v instanceof Float32Array && v.join()"},
+		{[]float64{-1, 2}, "v instanceof Float64Array && v.join()"},
+	}
+	for _, test := range tests {
+		ctx.GlobalObject().SetProperty("v", ctx.reflectToJSValue(reflect.ValueOf(test.value)), 0)
+		val, err := ctx.EvaluateScript(test.script, nil, "", 1)
+		if err != nil {
+			t.Errorf("%T: ctx.EvaluateScript failed: %v", test.value, err)
+			continue
+		}
+		if s := val.ToStringOrDie(); s != "-1,2" && s != "1,2" {
+			t.Errorf("%T converted to %s", test.value, s)
+		}
+	}
+	if v := ctx.reflectToJSValue(reflect.ValueOf([]byte(nil))); !v.IsNull() {
+		t.Errorf("nil []byte converted to %v, want null", v)
+	}
+}
+
+func TestNativeFunctionTypedArrayArgs(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	sum := ctx.NewFunctionWithNative(func(data []byte) float64 {
+		n := 0
+		for _, b := range data {
+			n += int(b)
+		}
+		return float64(n)
+	})
+	scale := ctx.NewFunctionWithNative(func(v []float32) []float32 {
+		for i := range v {
+			v[i] *= 2
+		}
+		return v
+	})
+	ctx.GlobalObject().SetProperty("sum", sum.ToValue(), 0)
+	ctx.GlobalObject().SetProperty("scale", scale.ToValue(), 0)
+
+	checkScript(t, ctx, "sum(new Uint8Array([1, 2, 3]))", "6")
+	checkScript(t, ctx, "sum(new Uint8Array([1, 2, 3]).buffer)", "6")
+	checkScript(t, ctx, "var f = new Float32Array([1, 2.5]); scale(f).join() + ' ' + f.join()", "2,5 1,2.5")
+
+	if _, err := ctx.EvaluateScript("sum(new Int32Array(1))", nil, "", 1); err == nil {
+		t.Errorf("passing an Int32Array for []byte succeeded")
+	}
+}
+
+func TestNativeFunctionPlainArrayArgs(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	total := func(xs []float64) float64 {
+		n := 0.0
+		for _, x := range xs {
+			n += x
+		}
+		return n
+	}
+	global := ctx.GlobalObject()
+	global.SetProperty("total", ctx.NewFunctionWithNative(total).ToValue(), 0)
+	global.SetProperty("describe", ctx.NewOverloadedFunction(
+		func(s string) string { return "string " + s },
+		func(xs []float64) string { return "total " + strconv.FormatFloat(total(xs), 'f', -1, 64) },
+	).ToValue(), 0)
+
+	checkScript(t, ctx, "total([1, 2, 3.5])", "6.5")
+	checkScript(t, ctx, "total(new Float64Array([1, 2]))", "3")
+	checkScript(t, ctx, "describe([1, 2, 3.5])", "total 6.5")
+	checkScript(t, ctx, "describe(new Float64Array([1, 2]))", "total 3")
+	checkScript(t, ctx, "describe('x')", "string x")
+}
+
+func checkScript(t *testing.T, ctx *Context, script, want string) {
+	t.Helper()
+	val, err := ctx.EvaluateScript(script, nil, "", 1)
+	if err != nil {
+		t.Errorf("%s: ctx.EvaluateScript failed: %v", script, err)
+		return
+	}
+	if s := val.ToStringOrDie(); s != want {
+		t.Errorf("%s = %s, want %s", script, s, want)
+	}
+}
@@ -0,0 +1,69 @@
+package gojs
+
+import (
+	"strings"
+	"testing"
+)
+
+type unwrapUser struct {
+	Name string
+}
+
+type unwrapDB struct {
+	saved []*unwrapUser
+}
+
+func (db *unwrapDB) Save(user *unwrapUser) int {
+	db.saved = append(db.saved, user)
+	return len(db.saved)
+}
+
+func TestObject_GoObject(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	user := &unwrapUser{Name: "ada"}
+	obj := ctx.NewNativeObject(user)
+	if goObj, ok := obj.GoObject(); !ok || goObj != user {
+		t.Errorf("obj.GoObject() = %v, %v, want the wrapped pointer", goObj, ok)
+	}
+	if goObj, err := obj.ToValue().GoValue(); err != nil || goObj != user {
+		t.Errorf("GoValue() = %v, %v, want the wrapped pointer", goObj, err)
+	}
+	if _, ok := ctx.NewEmptyObject().GoObject(); ok {
+		t.Errorf("GoObject() of a plain object succeeded")
+	}
+	if _, ok := ctx.NewFunctionWithNative(func() {}).GoObject(); ok {
+		t.Errorf("GoObject() of a native function succeeded")
+	}
+}
+
+func TestUnwrapNativeArguments(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	db := &unwrapDB{}
+	user := &unwrapUser{Name: "ada"}
+	global := ctx.GlobalObject()
+	global.SetProperty("db", ctx.NewNativeObject(db).ToValue(), 0)
+	global.SetProperty("user", ctx.NewNativeObject(user).ToValue(), 0)
+	global.SetProperty("greet", ctx.NewFunctionWithNative(func(u unwrapUser) string {
+		return "hello " + u.Name
+	}).ToValue(), 0)
+
+	checkScript(t, ctx, "db.Save(user)", "1")
+	if len(db.saved) != 1 || db.saved[0] != user {
+		t.Errorf("db.Save(user) saved %v, want the wrapped pointer", db.saved)
+	}
+	checkScript(t, ctx, "greet(user)", "hello ada")
+
+	_, err := ctx.EvaluateScript("db.Save(db)", nil, "", 1)
+	if err == nil || !strings.Contains(err.Error(), "cannot convert native *gojs.unwrapDB to *gojs.unwrapUser") {
+		t.Errorf("db.Save(db) returned %v, want a type mismatch", err)
+	}
+
+	got, err := As[*unwrapUser](ctx.NewNativeObject(user).ToValue())
+	if err != nil || got != user {
+		t.Errorf("As[*unwrapUser] = %v, %v, want the wrapped pointer", got, err)
+	}
+}
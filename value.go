@@ -15,6 +15,7 @@ type Value struct {
 	ctx *Context
 }
 
+// The types of values returned by Type. Kind is the typed equivalent.
 const (
 	TypeUndefined = 0
 	TypeNull      = iota
@@ -22,6 +23,8 @@ const (
 	TypeNumber    = iota
 	TypeString    = iota
 	TypeObject    = iota
+	TypeSymbol    = iota
+	TypeBigInt    = iota
 )
 
 func (ctx *Context) newValue(ref C.JSValueRef) *Value {
@@ -65,6 +68,17 @@ func (ctx *Context) NewStringValue(value string) *Value {
 	return ctx.newValue(ref)
 }
 
+// NewValueFromJSON parses the JSON text data into a value, like JSON.parse.
+func (ctx *Context) NewValueFromJSON(data []byte) (*Value, error) {
+	jsstr := NewString(string(data))
+	defer jsstr.Release()
+	ret := C.JSValueMakeFromJSONString(ctx.ref, C.JSStringRef(unsafe.Pointer(jsstr)))
+	if ret == nil {
+		return nil, fmt.Errorf("gojs: invalid JSON")
+	}
+	return ctx.newValue(ret), nil
+}
+
 func (v *Value) String() string {
 	str, err := v.ToString()
 	if err != nil {
@@ -73,9 +87,32 @@ func (v *Value) String() string {
 	return str
 }
 
-// GoVal converts a JavaScript value to a Go value. TODO(sqs): might it be
-// easier to just have JavaScriptCore serialize this to JSON and then
-// deserialize it in Go?
+// goValueJSONSource evaluates to a function serializing a value to JSON
+// like JSON.stringify, except that Maps become objects, with keys converted
+// to strings, and Sets become arrays.
+const goValueJSONSource = `(function (value) {
+	var hasMap = typeof Map === "function", hasSet = typeof Set === "function";
+	return JSON.stringify(value, function (key, value) {
+		if (hasMap && value instanceof Map) {
+			var obj = {};
+			value.forEach(function (v, k) { obj[String(k)] = v; });
+			return obj;
+		}
+		if (hasSet && value instanceof Set) {
+			var arr = [];
+			value.forEach(function (v) { arr.push(v); });
+			return arr;
+		}
+		return value;
+	});
+})`
+
+// GoValue converts a JavaScript value to a Go value. Native objects convert
+// to the Go values they wrap, and Dates to time.Time. Other objects are
+// converted through their JSON representation, with Maps converted to
+// map[string]interface{} and Sets to []interface{}, and Dates nested in
+// them to strings. Objects JSON cannot represent, like functions, convert
+// to nil.
 func (v *Value) GoValue() (goval interface{}, err error) {
 	switch v.Type() {
 	case TypeUndefined, TypeNull:
@@ -87,18 +124,33 @@ func (v *Value) GoValue() (goval interface{}, err error) {
 	case TypeString:
 		return v.ToString()
 	case TypeObject:
-		jsonData, err := v.ToJSON()
+		if goObj, ok := v.ToObjectOrDie().GoObject(); ok {
+			return goObj, nil
+		}
+		if v.IsDate() {
+			t, err := v.Time()
+			if err != nil {
+				return nil, err
+			}
+			return t, nil
+		}
+		jsonData, err := v.ctx.callHelper(goValueJSONSource, v)
 		if err != nil {
 			return nil, err
 		}
-		err = json.Unmarshal(jsonData, &goval)
+		if !jsonData.IsString() {
+			return nil, nil
+		}
+		err = json.Unmarshal([]byte(jsonData.ToStringOrDie()), &goval)
 		return goval, err
 	}
 	return nil, fmt.Errorf("JS value type %d is not convertible to a Go value", v.Type())
 }
 
+// Type returns the type of v as one of the Type constants. It is the same
+// as Kind, untyped.
 func (v *Value) Type() uint8 {
-	return uint8(C.JSValueGetType(v.ctx.ref, v.ref))
+	return uint8(v.Kind())
 }
 
 func (v *Value) IsUndefined() bool {
@@ -215,5 +267,5 @@ func (v *Value) Protect() {
 }
 
 func (v *Value) UnProtect() {
-	C.JSValueProtect(v.ctx.ref, v.ref)
+	C.JSValueUnprotect(v.ctx.ref, v.ref)
 }
@@ -3,6 +3,7 @@ package gojs
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestValue_GoValue(t *testing.T) {
@@ -22,9 +23,7 @@ func TestValue_GoValue(t *testing.T) {
 		{ctx.NewStringValue(""), ""},
 		{ctx.NewStringValue("foo"), "foo"},
 		{ctx.NewEmptyObject().ToValue(), map[string]interface{}{}},
-		// TODO(sqs): convert JavaScript Date objects to Go time.Time (currently
-		// they are converted to a Go string)
-		// {jsObjectToJSValue(ctx.NewDateWithMilliseconds(123)), time.Unix(0, 123000000).In(time.UTC)},
+		{jsObjectToJSValue(ctx.NewDateWithMilliseconds(123)), time.Unix(0, 123000000).In(time.UTC)},
 		{
 			jsObjectToJSValue(ctx.NewArray([]*Value{ctx.NewStringValue("foo")})),
 			[]interface{}{"foo"},
@@ -47,6 +46,38 @@ func TestValue_GoValue(t *testing.T) {
 	}
 }
 
+func TestValue_GoValueMapSet(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	tests := []struct {
+		script      string
+		wantGoValue interface{}
+	}{
+		{`new Map([["a", 1], [2, "b"]])`, map[string]interface{}{"a": 1.0, "2": "b"}},
+		{`new Set(["x", "y", "x"])`, []interface{}{"x", "y"}},
+		{`({ tags: new Set([1]), byName: new Map([["n", [true]]]) })`, map[string]interface{}{
+			"tags":   []interface{}{1.0},
+			"byName": map[string]interface{}{"n": []interface{}{true}},
+		}},
+		{`(function () {})`, nil},
+	}
+	for _, test := range tests {
+		val, err := ctx.EvaluateScript(test.script, nil, "", 1)
+		if err != nil {
+			t.Fatalf("ctx.EvaluateScript(%q) failed: %v", test.script, err)
+		}
+		goValue, err := val.GoValue()
+		if err != nil {
+			t.Errorf("%s: GoValue error: %s", test.script, err)
+			continue
+		}
+		if !reflect.DeepEqual(test.wantGoValue, goValue) {
+			t.Errorf("%s: want GoValue %+v, got %+v", test.script, test.wantGoValue, goValue)
+		}
+	}
+}
+
 func jsObjectToJSValue(obj *Object, err error) *Value {
 	if err != nil {
 		panic("object creation failed: " + err.Error())
@@ -0,0 +1,86 @@
+package gojs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// wrapperCacheSource evaluates to a function creating the cache of the
+// wrappers of Go pointers of a context. It holds them through WeakRefs, so
+// that wrappers are collected once scripts drop them, and forgets them
+// through a FinalizationRegistry. Without WeakRef, nothing is cached.
+const wrapperCacheSource = `(function () {
+	"use strict";
+	var refs = new Map();
+	var registry = typeof FinalizationRegistry === "function" ? new FinalizationRegistry(function (key) {
+		var ref = refs.get(key);
+		if (ref !== undefined && ref.deref() === undefined) {
+			refs.delete(key);
+		}
+	}) : null;
+	return {
+		get: function (key) {
+			var ref = refs.get(key);
+			return ref === undefined ? undefined : ref.deref();
+		},
+		set: function (key, obj) {
+			if (typeof WeakRef !== "function") {
+				return;
+			}
+			refs.set(key, new WeakRef(obj));
+			if (registry !== null) {
+				registry.register(obj, key);
+			}
+		}
+	};
+})`
+
+// wrapperKey returns the key of the wrapper of the Go value val, or of its
+// member with the given name, in the wrapper cache. Only pointers have
+// identities to preserve; it returns false for other values.
+func wrapperKey(val reflect.Value, member string) (string, bool) {
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return "", false
+	}
+	// The address alone is ambiguous: a struct and its first field share it.
+	return fmt.Sprintf("%x %p %s", val.Pointer(), val.Type(), member), true
+}
+
+// wrapperCache returns the wrapper cache of ctx, creating it on first use.
+// It stays protected until the context is released.
+func (ctx *Context) wrapperCache() (*Object, error) {
+	data := ctx.data()
+	if data.wrappers != nil {
+		return data.wrappers, nil
+	}
+	ret, err := ctx.callHelper(wrapperCacheSource)
+	if err != nil {
+		return nil, err
+	}
+	cache := ret.ToObjectOrDie()
+	cache.ToValue().Protect()
+	data.wrappers = cache
+	return cache, nil
+}
+
+// cachedWrapper returns the wrapper cached under key, or nil if there is
+// none.
+func (ctx *Context) cachedWrapper(key string) *Object {
+	cache, err := ctx.wrapperCache()
+	if err != nil {
+		return nil
+	}
+	ret, err := cache.Call("get", key)
+	if err != nil || !ret.IsObject() {
+		return nil
+	}
+	return ret.ToObjectOrDie()
+}
+
+// cacheWrapper caches wrapper under key, for as long as scripts refer to
+// it. Wrappers are only created anew when caching fails.
+func (ctx *Context) cacheWrapper(key string, wrapper *Object) {
+	if cache, err := ctx.wrapperCache(); err == nil {
+		cache.Call("set", key, wrapper)
+	}
+}
@@ -0,0 +1,49 @@
+package gojs
+
+import (
+	"testing"
+)
+
+type wrapperNode struct {
+	Name   string
+	Parent *wrapperNode
+}
+
+func (n *wrapperNode) Path() string {
+	if n.Parent == nil {
+		return n.Name
+	}
+	return n.Parent.Path() + "/" + n.Name
+}
+
+func TestNativeObjectIdentity(t *testing.T) {
+	ctx := NewContext()
+	defer ctx.Release()
+
+	root := &wrapperNode{Name: "root"}
+	a := &wrapperNode{Name: "a", Parent: root}
+	b := &wrapperNode{Name: "b", Parent: root}
+
+	global := ctx.GlobalObject()
+	global.SetProperty("a", ctx.NewNativeObject(a).ToValue(), 0)
+	global.SetProperty("b", ctx.NewNativeObject(b).ToValue(), 0)
+	global.SetProperty("root", ctx.NewNativeObject(root).ToValue(), 0)
+
+	checkScript(t, ctx, "a.Parent === b.Parent", "true")
+	checkScript(t, ctx, "a.Parent === root", "true")
+	checkScript(t, ctx, "a.Path === a.Path", "true")
+	checkScript(t, ctx, "a.Path !== b.Path", "true")
+	checkScript(t, ctx, "a.Path()", "root/a")
+	checkScript(t, ctx, "var seen = new WeakMap(); seen.set(a.Parent, 1); seen.get(b.Parent)", "1")
+
+	if ctx.NewNativeObject(a).ToValue().ref != ctx.NewNativeObject(a).ToValue().ref {
+		t.Errorf("wrapping the same pointer twice returned different objects")
+	}
+	value := wrapperNode{Name: "value"}
+	if ctx.NewNativeObject(value).ToValue().ref == ctx.NewNativeObject(value).ToValue().ref {
+		t.Errorf("wrapping a struct value twice returned the same object")
+	}
+
+	ctx.GarbageCollect()
+	checkScript(t, ctx, "a.Parent === root", "true")
+}